@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	flags "github.com/jessevdk/go-flags"
+	"gopkg.in/yaml.v3"
+)
+
+// configFile is the layout of a --config file.  The top-level
+// settings apply to every job; each job can override them.
+type configFile struct {
+	cfg  `yaml:",inline"`
+	Jobs []yaml.Node `yaml:"jobs"`
+}
+
+// loadJobs returns the jobs to run.  Without a config file the
+// command line is the only job.  Otherwise each job starts from the
+// command line defaults, then the file's top-level settings, then the
+// job's own settings, and finally any flags given on the command line.
+func loadJobs(parser *flags.Parser, cli *cfg) ([]*cfg, error) {
+	if cli.Config == "" {
+		return []*cfg{cli}, nil
+	}
+	data, err := readConfigFile(cli.Config)
+	if err != nil {
+		return nil, err
+	}
+	file := configFile{cfg: *cli}
+	if err := decodeStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", cli.Config, err)
+	}
+
+	var jobs []*cfg
+	if len(file.Jobs) == 0 {
+		job := file.cfg
+		jobs = append(jobs, &job)
+	}
+	for i := range file.Jobs {
+		job := file.cfg
+		job.Name = ""
+		if err := checkKeys(&file.Jobs[i]); err != nil {
+			return nil, fmt.Errorf("%s: %w", cli.Config, err)
+		}
+		if err := file.Jobs[i].Decode(&job); err != nil {
+			return nil, fmt.Errorf("%s: %w", cli.Config, err)
+		}
+		if job.Name == "" {
+			return nil, fmt.Errorf("%s: job at line %d has no name", cli.Config, file.Jobs[i].Line)
+		}
+		jobs = append(jobs, &job)
+	}
+
+	jobs, err = selectJobs(jobs, cli.Jobs)
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range jobs {
+		applyFlags(parser, cli, job)
+	}
+	return jobs, nil
+}
+
+// readConfigFile returns the config file as YAML.  TOML files are
+// converted so both formats are decoded the same way.
+func readConfigFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.ToLower(filepath.Ext(path)) != ".toml" {
+		return data, nil
+	}
+	var settings map[string]interface{}
+	if err := toml.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return yaml.Marshal(settings)
+}
+
+// decodeStrict decodes YAML into v, rejecting unknown keys
+func decodeStrict(data []byte, v interface{}) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	return decoder.Decode(v)
+}
+
+// checkKeys returns an error for the first key of a job that isn't
+// a setting.  yaml.Node.Decode can't reject unknown keys itself.
+func checkKeys(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: a job must be a mapping of settings", node.Line)
+	}
+	known := make(map[string]bool)
+	t := reflect.TypeOf(cfg{})
+	for i := 0; i < t.NumField(); i++ {
+		known[strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]] = true
+	}
+	delete(known, "-")
+	for i := 0; i < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if !known[key.Value] {
+			return fmt.Errorf("line %d: unknown setting %s", key.Line, key.Value)
+		}
+	}
+	return nil
+}
+
+// selectJobs returns the named jobs, or every job when no names are
+// given
+func selectJobs(jobs []*cfg, names []string) ([]*cfg, error) {
+	if len(names) == 0 {
+		return jobs, nil
+	}
+	var selected []*cfg
+	for _, name := range names {
+		found := false
+		for _, job := range jobs {
+			if job.Name == name {
+				selected = append(selected, job)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no job named %s", name)
+		}
+	}
+	return selected, nil
+}
+
+// applyFlags copies the options given on the command line into the
+// job.  Defaults and environment variables don't override the file.
+func applyFlags(parser *flags.Parser, cli, job *cfg) {
+	from := reflect.ValueOf(cli).Elem()
+	to := reflect.ValueOf(job).Elem()
+	for _, group := range parser.Groups() {
+		for _, option := range group.Options() {
+			if !option.IsSet() || option.IsSetDefault() {
+				continue
+			}
+			index := option.Field().Index
+			to.FieldByIndex(index).Set(from.FieldByIndex(index))
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	flags "github.com/jessevdk/go-flags"
+)
+
+func Test_loadJobs(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile := filepath.Join(dir, "wbspert.yaml")
+	os.WriteFile(yamlFile, []byte(`input: tasks.csv
+level: 2
+jobs:
+  - name: pert
+    pert: true
+    output: pert.puml
+  - name: table
+    table: true
+    level: 4
+`), 0644)
+	tomlFile := filepath.Join(dir, "wbspert.toml")
+	os.WriteFile(tomlFile, []byte(`input = "tasks.csv"
+[[jobs]]
+name = "table"
+table = true
+`), 0644)
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantNames []string
+		wantLevel []int
+		wantErr   bool
+	}{
+		{"no config", []string{"-p"}, []string{""}, []int{3}, false},
+		{"file settings", []string{"--config", yamlFile}, []string{"pert", "table"}, []int{2, 4}, false},
+		{"flag overrides", []string{"--config", yamlFile, "-l", "5"}, []string{"pert", "table"}, []int{5, 5}, false},
+		{"select job", []string{"--config", yamlFile, "--job", "table"}, []string{"table"}, []int{4}, false},
+		{"unknown job", []string{"--config", yamlFile, "--job", "gantt"}, nil, nil, true},
+		{"toml", []string{"--config", tomlFile}, []string{"table"}, []int{3}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &cfg{}
+			parser := flags.NewParser(config, flags.None)
+			if _, err := parser.ParseArgs(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			jobs, err := loadJobs(parser, config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadJobs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(jobs) != len(tt.wantNames) {
+				t.Fatalf("loadJobs() returned %d jobs, want %d", len(jobs), len(tt.wantNames))
+			}
+			for i, job := range jobs {
+				if job.Name != tt.wantNames[i] || job.Level != tt.wantLevel[i] {
+					t.Errorf("loadJobs()[%d] = %s level %d, want %s level %d", i, job.Name, job.Level, tt.wantNames[i], tt.wantLevel[i])
+				}
+				if tt.args[0] == "--config" && job.Input != "tasks.csv" {
+					t.Errorf("loadJobs()[%d].Input = %s, want tasks.csv", i, job.Input)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,314 @@
+// Command wbspert generates PERT charts, work breakdown structures and
+// tables from a CSV spreadsheet, an Excel workbook, a YAML or JSON task
+// file or a GitHub project.
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ghprojects/projects"
+
+	flags "github.com/jessevdk/go-flags"
+
+	"wbspert/pkg/render"
+	"wbspert/pkg/wbs"
+)
+
+type cfg struct {
+	Input        string   `short:"i" default:"-" description:"The input file or - for stdin" yaml:"input"`
+	InputFormat  string   `long:"input-format" choice:"csv" choice:"xlsx" choice:"yaml" choice:"json" description:"The input format (default: from the file extension, or csv)" yaml:"inputFormat"`
+	Sheet        string   `long:"sheet" description:"The sheet to read from an .xlsx input (default: the first sheet)" yaml:"sheet"`
+	Output       string   `short:"o" default:"-" description:"The output file or - for stdout" yaml:"output"`
+	AutoNumber   bool     `long:"auto-number" description:"Assign the WBS codes from the Parent column or the title indentation" yaml:"autoNumber"`
+	WriteSheet   string   `long:"write-sheet" description:"Write the renumbered tasks to a CSV file" yaml:"writeSheet"`
+	Level        int      `short:"l" default:"3" description:"The WBS level to use for PERT charts" yaml:"level"`
+	WBS          bool     `short:"w"  description:"Generate the WBS" yaml:"wbs"`
+	PERT         bool     `short:"p"  description:"Generate the PERT" yaml:"pert"`
+	Gantt        bool     `short:"g" description:"Generate the Gantt chart" yaml:"gantt"`
+	Start        string   `long:"start" description:"Project start date (YYYY-MM-DD) for the Gantt chart" yaml:"start"`
+	Calendar     string   `long:"calendar" description:"YAML file with the project calendar: start, unit, weekdays, hours per day and holidays" yaml:"calendar"`
+	Table        bool     `short:"t" description:"Generate Markdown Table" yaml:"table"`
+	Embed        bool     `short:"e" description:"Embed in an existing file" yaml:"embed"`
+	Token        string   `long:"token" env:"GITHUB_TOKEN" long:"github-token" description:"Access token for calling Github API" yaml:"token"`
+	Org          string   `long:"org" default:"ringsq" description:"Github org containing the project" yaml:"org"`
+	Project      string   `short:"j" long:"project" description:"Github Project name" yaml:"project"`
+	ByRepo       bool     `short:"r" description:"Do WBS by repo name" yaml:"byRepo"`
+	Kanban       bool     `short:"k" description:"Build a kanban table" yaml:"kanban"`
+	Column       string   `short:"c" default:"Status" description:"Column field for Kanban table" yaml:"column"`
+	BugList      bool     `short:"b" description:"Generate a buglist" yaml:"bugList"`
+	EpicList     bool     `short:"E" long:"epiclist" description:"Generate a checklist of epics" yaml:"epicList"`
+	ActiveOnly   bool     `short:"a" description:"Only show incomplete tasks" yaml:"activeOnly"`
+	Statuses     string   `long:"statuses" description:"YAML file mapping status names to categories, colors and legend labels" yaml:"statuses"`
+	KeepOrder    bool     `long:"keep-order" description:"Keep the input order instead of sorting the tasks by WBS code" yaml:"keepOrder"`
+	EpicDir      string   `short:"d" description:"The location to write epic stories" yaml:"epicDir"`
+	EpicStories  bool     `short:"s" description:"Write epic stories" yaml:"epicStories"`
+	Filter       string   `short:"f" long:"filter" description:"Filter WBS Table and Kanban by a label value" yaml:"filter"`
+	CriticalOnly bool     `long:"critical-only" description:"Only show the critical path in the PERT chart" yaml:"criticalOnly"`
+	Simulate     int      `long:"simulate" description:"Run a Monte Carlo schedule simulation with the given number of trials" yaml:"simulate"`
+	Distribution string   `long:"distribution" default:"beta" choice:"beta" choice:"triangular" description:"Distribution used to sample task durations in the simulation" yaml:"distribution"`
+	Seed         int64    `long:"seed" description:"Random seed for the simulation (default: current time)" yaml:"seed"`
+	Load         bool     `long:"load" description:"Report the periods in which an assignee is over-allocated" yaml:"load"`
+	LoadPeriod   string   `long:"load-period" default:"day" choice:"day" choice:"week" description:"Period the resource load is reported by" yaml:"loadPeriod"`
+	Leveling     bool     `long:"level-resources" description:"Delay tasks so no assignee is double-booked" yaml:"levelResources"`
+	LevelReport  bool     `long:"leveling-report" description:"Report the tasks moved by resource leveling" yaml:"levelingReport"`
+	Milestones   bool     `long:"milestones" description:"Report the target and forecast date of each milestone" yaml:"milestones"`
+	Validate     bool     `long:"validate" description:"Only check the tasks for cycles, unknown parents and duplicate IDs" yaml:"validate"`
+	Format       string   `long:"format" default:"plantuml" choice:"plantuml" choice:"mermaid" choice:"dot" choice:"svg" description:"Diagram format for the PERT, WBS and Gantt charts (dot is PERT only, svg is PERT and WBS only)" yaml:"format"`
+	PertOut      string   `long:"pert-out" yaml:"pertOut" description:"Write the PERT chart to its own file (file#tag embeds it)"`
+	WBSOut       string   `long:"wbs-out" yaml:"wbsOut" description:"Write the WBS to its own file (file#tag embeds it)"`
+	GanttOut     string   `long:"gantt-out" yaml:"ganttOut" description:"Write the Gantt chart to its own file (file#tag embeds it)"`
+	TableOut     string   `long:"table-out" yaml:"tableOut" description:"Write the Markdown table to its own file (file#tag embeds it)"`
+	KanbanOut    string   `long:"kanban-out" yaml:"kanbanOut" description:"Write the kanban table to its own file (file#tag embeds it)"`
+	BugOut       string   `long:"bug-out" yaml:"bugOut" description:"Write the buglist to its own file (file#tag embeds it)"`
+	EpicOut      string   `long:"epic-out" yaml:"epicOut" description:"Write the epic checklist to its own file (file#tag embeds it)"`
+	LoadOut      string   `long:"load-out" yaml:"loadOut" description:"Write the over-allocation report to its own file (file#tag embeds it)"`
+	LevelOut     string   `long:"leveling-out" yaml:"levelingOut" description:"Write the resource leveling report to its own file (file#tag embeds it)"`
+	MilestoneOut string   `long:"milestones-out" yaml:"milestonesOut" description:"Write the milestone report to its own file (file#tag embeds it)"`
+	SimOut       string   `long:"sim-out" yaml:"simOut" description:"Write the simulation results to their own file (file#tag embeds them)"`
+	Config       string   `long:"config" yaml:"-" description:"YAML or TOML file with settings and named jobs"`
+	Jobs         []string `long:"job" yaml:"-" description:"Only run the named job from the config file (repeatable)"`
+	Name         string   `no-flag:"true" yaml:"name"`
+}
+
+// options returns the render options set by the command line
+func (c *cfg) options() *render.Options {
+	return &render.Options{
+		Level:          c.Level,
+		ActiveOnly:     c.ActiveOnly,
+		Filter:         c.Filter,
+		Column:         c.Column,
+		CriticalOnly:   c.CriticalOnly,
+		Format:         c.Format,
+		Start:          c.Start,
+		Trials:         c.Simulate,
+		Distribution:   c.Distribution,
+		Seed:           c.Seed,
+		EpicDir:        c.EpicDir,
+		KeepOrder:      c.KeepOrder,
+		LoadPeriod:     c.LoadPeriod,
+		LevelResources: c.Leveling,
+	}
+}
+
+// errInvalidTasks is returned when --validate finds problems
+var errInvalidTasks = errors.New("invalid tasks")
+
+func main() {
+	config := &cfg{}
+	parser := flags.NewParser(config, flags.Default)
+	if _, err := parser.Parse(); err != nil {
+		log.Fatal(err)
+	}
+	jobs, err := loadJobs(parser, config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	invalid := false
+	for _, job := range jobs {
+		if job.Name != "" {
+			log.Printf("Running job %s", job.Name)
+		}
+		err := runJob(job)
+		if err == errInvalidTasks {
+			invalid = true
+		} else if err != nil && job.Name != "" {
+			log.Fatalf("%s: %s", job.Name, err)
+		} else if err != nil {
+			log.Fatal(err)
+		}
+	}
+	if invalid {
+		os.Exit(1)
+	}
+}
+
+// runJob loads the tasks for one job and writes its outputs
+func runJob(config *cfg) error {
+	if strings.HasSuffix(strings.ToLower(config.Output), ".svg") {
+		config.Format = render.SVGFormat
+	}
+	switch config.Format {
+	case render.PlantUMLFormat, render.MermaidFormat, render.DotFormat, render.SVGFormat:
+	default:
+		return fmt.Errorf("unknown format %s", config.Format)
+	}
+	if config.Distribution != wbs.BetaDistribution && config.Distribution != wbs.TriangularDistribution {
+		return fmt.Errorf("unknown distribution %s", config.Distribution)
+	}
+	statuses, err := loadStatuses(config.Statuses)
+	if err != nil {
+		return err
+	}
+	targets := config.targets()
+	if err := config.checkFormats(targets); err != nil {
+		return err
+	}
+
+	sheets, board, err := load(config)
+	if err != nil {
+		return err
+	}
+	if config.AutoNumber {
+		if sheets, err = wbs.AutoNumber(sheets); err != nil {
+			return err
+		}
+	}
+	if config.WriteSheet != "" {
+		if err := writeSheet(config.WriteSheet, sheets); err != nil {
+			return err
+		}
+	}
+
+	problems := wbs.Validate(wbs.WithStatuses(sheets, statuses))
+	if config.Validate {
+		fmt.Print(wbs.ValidationReport(problems))
+		if len(problems) > 0 {
+			return errInvalidTasks
+		}
+		return nil
+	}
+	if charts := config.charts(); charts.PERT || charts.WBS || charts.Gantt {
+		for _, problem := range problems {
+			log.Printf("warning: %s", problem)
+		}
+	}
+
+	dst := render.Output{Writer: os.Stdout, Path: config.Output, Embed: config.Embed && config.Output != "-"}
+	closeOutputs, err := openOutputs(&dst, targets)
+	if err != nil {
+		return err
+	}
+	defer closeOutputs()
+
+	opts := config.options()
+	opts.Statuses = statuses
+	if opts.Calendar, err = loadCalendar(config); err != nil {
+		return err
+	}
+	return render.Generate(dst, targets, config.charts(), sheets, board, opts)
+}
+
+// load reads the tasks from the input file, stdin or GitHub.  The
+// board is only returned for GitHub projects.
+func load(config *cfg) ([]wbs.Sheet, *projects.Board, error) {
+	if config.Input == "gh" {
+		client := projects.NewClient(context.Background(), config.Token)
+		board, sheets, err := wbs.LoadProject(client, config.Org, config.Project, config.ByRepo)
+		return sheets, board, err
+	}
+	format := config.inputFormat()
+	if format == wbs.XLSXFormat && config.Input != "-" {
+		sheets, err := wbs.ReadXLSXFile(config.Input, config.Sheet)
+		return sheets, nil, err
+	}
+
+	in := os.Stdin
+	if config.Input != "-" {
+		file, err := os.Open(config.Input)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer file.Close()
+		in = file
+	}
+	var sheets []wbs.Sheet
+	var err error
+	switch format {
+	case wbs.XLSXFormat:
+		var data []byte
+		if data, err = io.ReadAll(in); err == nil {
+			sheets, err = wbs.ReadXLSX(bytes.NewReader(data), int64(len(data)), config.Sheet)
+		}
+	case wbs.YAMLFormat, wbs.JSONFormat:
+		sheets, err = wbs.ReadTasks(in)
+	default:
+		sheets, err = wbs.ReadFile(in)
+	}
+	return sheets, nil, err
+}
+
+// loadStatuses reads the status vocabulary from the file at path.
+// Without a file no vocabulary is returned and the defaults are used.
+func loadStatuses(path string) ([]wbs.StatusStyle, error) {
+	if path == "" {
+		return nil, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	styles, err := wbs.ReadStatuses(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return styles, nil
+}
+
+// loadCalendar reads the --calendar file.  --start is used when the
+// file has no start date.  Without a file no calendar is returned.
+func loadCalendar(config *cfg) (*wbs.Calendar, error) {
+	if config.Calendar == "" {
+		return nil, nil
+	}
+	var start time.Time
+	if config.Start != "" {
+		var err error
+		if start, err = time.Parse(wbs.DateFormat, config.Start); err != nil {
+			return nil, fmt.Errorf("invalid start date %s: %s", config.Start, err)
+		}
+	}
+	return wbs.ReadCalendar(config.Calendar, start)
+}
+
+// writeSheet writes the tasks to a CSV file
+func writeSheet(path string, sheets []wbs.Sheet) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := wbs.WriteFile(file, sheets); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// inputFormat returns the format of the input file, taken from
+// --input-format or the file extension
+func (c *cfg) inputFormat() string {
+	if c.InputFormat != "" {
+		return c.InputFormat
+	}
+	if format := wbs.InputFormat(filepath.Ext(c.Input)); format != "" {
+		return format
+	}
+	return wbs.CSVFormat
+}
+
+// charts returns the outputs enabled on the command line
+func (c *cfg) charts() render.Charts {
+	return render.Charts{
+		PERT:        c.PERT || c.PertOut != "",
+		WBS:         c.WBS || c.WBSOut != "",
+		Gantt:       c.Gantt || c.GanttOut != "",
+		Table:       c.Table || c.TableOut != "",
+		Kanban:      c.Kanban || c.KanbanOut != "",
+		BugList:     c.BugList || c.BugOut != "",
+		EpicList:    c.EpicList || c.EpicOut != "",
+		EpicStories: c.EpicStories,
+		Simulation:  c.Simulate > 0,
+		Load:        c.Load || c.LoadOut != "",
+		Leveling:    c.LevelReport || c.LevelOut != "",
+		Milestones:  c.Milestones || c.MilestoneOut != "",
+	}
+}
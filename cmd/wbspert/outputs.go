@@ -0,0 +1,165 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"wbspert/pkg/render"
+)
+
+// chartOutputs maps each chart's embed tag to its --*-out setting
+func (c *cfg) chartOutputs() map[string]string {
+	return map[string]string{
+		render.PertTag:      c.PertOut,
+		render.WBSTag:       c.WBSOut,
+		render.GanttTag:     c.GanttOut,
+		render.WBSTableTag:  c.TableOut,
+		render.KanbanTag:    c.KanbanOut,
+		render.BugTag:       c.BugOut,
+		render.EpicTag:      c.EpicOut,
+		render.SimTag:       c.SimOut,
+		render.LoadTag:      c.LoadOut,
+		render.LevelingTag:  c.LevelOut,
+		render.MilestoneTag: c.MilestoneOut,
+	}
+}
+
+// formatFor returns the diagram format implied by the file extension,
+// or fallback when the extension doesn't name one
+func formatFor(path, fallback string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".svg":
+		return render.SVGFormat
+	case ".dot", ".gv":
+		return render.DotFormat
+	case ".mmd":
+		return render.MermaidFormat
+	case ".puml", ".plantuml":
+		return render.PlantUMLFormat
+	}
+	return fallback
+}
+
+// parseTarget reads an output setting.  "file" writes the chart to
+// the file on its own; "file#tag" embeds it under the tag, and
+// "file#" under the chart's usual tag.
+func parseTarget(spec, format string) render.Output {
+	if i := strings.LastIndex(spec, "#"); i >= 0 {
+		return render.Output{Path: spec[:i], Tag: spec[i+1:], Embed: true, Format: format}
+	}
+	return render.Output{Path: spec, Format: formatFor(spec, format)}
+}
+
+// targets returns the outputs of the charts written to their own file
+func (c *cfg) targets() map[string]render.Output {
+	targets := make(map[string]render.Output)
+	for tag, spec := range c.chartOutputs() {
+		if spec != "" {
+			targets[tag] = parseTarget(spec, c.Format)
+		}
+	}
+	return targets
+}
+
+// checkFormats rejects charts written in a format that can't hold them
+func (c *cfg) checkFormats(targets map[string]render.Output) error {
+	if c.SimOut != "" && c.Simulate <= 0 {
+		return errors.New("--sim-out needs --simulate")
+	}
+	charts := c.charts()
+	diagrams := []struct {
+		enabled bool
+		tag     string
+	}{
+		{charts.PERT, render.PertTag},
+		{charts.WBS, render.WBSTag},
+		{charts.Gantt, render.GanttTag},
+	}
+	images := make(map[string]int)
+	for _, diagram := range diagrams {
+		if !diagram.enabled {
+			continue
+		}
+		dst, ok := targets[diagram.tag]
+		if !ok {
+			dst = render.Output{Path: c.Output, Embed: c.Embed && c.Output != "-", Format: c.Format}
+		}
+		switch dst.Format {
+		case render.DotFormat:
+			if diagram.tag != render.PertTag {
+				return errors.New("the dot format is only supported for the PERT chart")
+			}
+		case render.SVGFormat:
+			if diagram.tag == render.GanttTag {
+				return errors.New("the svg format is only supported for the PERT and WBS charts")
+			}
+			if dst.Embed {
+				return errors.New("svg images can't be embedded in a document")
+			}
+			images[dst.Path]++
+			if images[dst.Path] > 1 {
+				return errors.New("only one of the PERT and WBS charts can be written to an svg image")
+			}
+		}
+	}
+	return nil
+}
+
+// openOutputs creates the files of the main output, unless it is
+// stdout or embedded, and of the plain targets.  Outputs sharing a
+// file share one handle and are written one after the other.  A file
+// can't be both written and embedded into.  The returned function
+// closes the files.
+func openOutputs(out *render.Output, targets map[string]render.Output) (func(), error) {
+	embedded := make(map[string]bool)
+	for _, target := range targets {
+		if target.Embed {
+			embedded[filepath.Clean(target.Path)] = true
+		}
+	}
+	if out.Embed {
+		embedded[filepath.Clean(out.Path)] = true
+	}
+
+	files := make(map[string]*os.File)
+	closeAll := func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}
+	open := func(output *render.Output) error {
+		path := filepath.Clean(output.Path)
+		if embedded[path] {
+			return fmt.Errorf("%s can't be both written and embedded into", output.Path)
+		}
+		file, ok := files[path]
+		if !ok {
+			var err error
+			if file, err = os.Create(output.Path); err != nil {
+				return err
+			}
+			files[path] = file
+		}
+		output.Writer = file
+		return nil
+	}
+	if !out.Embed && out.Path != "-" {
+		if err := open(out); err != nil {
+			return nil, err
+		}
+	}
+	for tag, target := range targets {
+		if target.Embed {
+			continue
+		}
+		if err := open(&target); err != nil {
+			closeAll()
+			return nil, err
+		}
+		targets[tag] = target
+	}
+	return closeAll, nil
+}
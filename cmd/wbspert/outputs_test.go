@@ -0,0 +1,87 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"wbspert/pkg/render"
+)
+
+func Test_parseTarget(t *testing.T) {
+	tests := []struct {
+		spec string
+		want render.Output
+	}{
+		{"docs/pert.puml", render.Output{Path: "docs/pert.puml", Format: render.PlantUMLFormat}},
+		{"docs/pert.svg", render.Output{Path: "docs/pert.svg", Format: render.SVGFormat}},
+		{"docs/pert.txt", render.Output{Path: "docs/pert.txt", Format: render.MermaidFormat}},
+		{"README.md#wbsTable", render.Output{Path: "README.md", Tag: "wbsTable", Embed: true, Format: render.MermaidFormat}},
+		{"README.md#", render.Output{Path: "README.md", Embed: true, Format: render.MermaidFormat}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			if got := parseTarget(tt.spec, render.MermaidFormat); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTarget() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_cfg_checkFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  cfg
+		wantErr bool
+	}{
+		{"separate images", cfg{Output: "-", Format: "plantuml", PertOut: "pert.svg", WBSOut: "wbs.svg"}, false},
+		{"same image", cfg{Output: "both.svg", Format: "svg", PERT: true, WBS: true}, true},
+		{"dot wbs", cfg{Output: "-", Format: "plantuml", PERT: true, WBSOut: "wbs.dot"}, true},
+		{"embedded image", cfg{Output: "-", Format: "svg", PertOut: "README.md#pert"}, true},
+		{"gantt image", cfg{Output: "-", Format: "plantuml", GanttOut: "gantt.svg"}, true},
+		{"simulation without trials", cfg{Output: "-", Format: "plantuml", SimOut: "sim.md"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.checkFormats(tt.config.targets()); (err != nil) != tt.wantErr {
+				t.Errorf("checkFormats() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_openOutputs(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "charts.puml")
+	out := render.Output{Writer: os.Stdout, Path: path}
+	targets := map[string]render.Output{
+		render.PertTag: {Path: path},
+		render.WBSTag:  {Path: filepath.Join(dir, ".", "charts.puml")},
+	}
+	closeOutputs, err := openOutputs(&out, targets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range []io.Writer{out.Writer, targets[render.PertTag].Writer, targets[render.WBSTag].Writer} {
+		if _, err := io.WriteString(w, "x"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	closeOutputs()
+	if data, err := os.ReadFile(path); err != nil || string(data) != "xxx" {
+		t.Errorf("openOutputs() wrote %q, %v, want one file shared by every output", data, err)
+	}
+
+	embedded := map[string]render.Output{render.PertTag: {Path: path, Embed: true}}
+	if _, err := openOutputs(&render.Output{Path: path}, embedded); err == nil {
+		t.Errorf("openOutputs() should reject a file that is written and embedded into")
+	}
+	stdout := render.Output{Writer: os.Stdout, Path: "-"}
+	if closeOutputs, err := openOutputs(&stdout, nil); err != nil || stdout.Writer != os.Stdout {
+		t.Errorf("openOutputs() of stdout = %v, %v", stdout.Writer, err)
+	} else {
+		closeOutputs()
+	}
+}
@@ -4,6 +4,7 @@ go 1.16
 
 require (
 	ghprojects v0.0.0-00010101000000-000000000000
+	github.com/BurntSushi/toml v0.4.1
 	github.com/jessevdk/go-flags v1.5.0
 	github.com/jinzhu/copier v0.3.5
 	github.com/jszwec/csvutil v1.6.0
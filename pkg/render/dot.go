@@ -0,0 +1,87 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"wbspert/pkg/wbs"
+)
+
+// dotRecord escapes the characters that have a meaning inside a
+// Graphviz record label
+var dotRecord = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "{", `\{`, "}", `\}`, "|", `\|`, "<", `\<`, ">", `\>`)
+
+// dotString escapes the characters that end a quoted Graphviz string
+var dotString = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// dotBranch returns the top-level WBS branch a task belongs to
+func dotBranch(wbs string) string {
+	return strings.SplitN(wbs, ".", 2)[0]
+}
+
+// pertDot renders the PERT network as a Graphviz digraph.  Tasks
+// are record shaped nodes grouped into one cluster per top-level
+// WBS branch.
+func pertDot(tasks []wbs.Sheet, links []pertLink, duration float32, stdDev float64, opts *Options) string {
+	var branches []string
+	nodes := make(map[string][]string)
+	for _, task := range tasks {
+		sched := task.Schedule
+		if opts.CriticalOnly && !sched.Critical {
+			continue
+		}
+		attrs := []string{
+			fmt.Sprintf(`label="{%s|%s|{ES %0.1f|Dur %0.1f|EF %0.1f}|{LS %0.1f|Slack %0.1f|LF %0.1f}}"`,
+				dotRecord.Replace(task.WBS), dotRecord.Replace(task.Title),
+				sched.ES, task.Expected(), sched.EF, sched.LS, sched.Slack, sched.LF),
+		}
+		if task.IsMilestone() {
+			attrs = []string{"shape=diamond", fmt.Sprintf(`label="%s\n%s\n%s"`,
+				dotString.Replace(task.WBS), dotString.Replace(task.Title), dotString.Replace(milestoneDay(&task)))}
+		}
+		if color := task.StatusColor(); color != "" {
+			attrs = append(attrs, fmt.Sprintf(`fillcolor="%s"`, color))
+		}
+		if sched.Critical {
+			attrs = append(attrs, "color=red", "penwidth=3")
+		}
+		branch := dotBranch(task.WBS)
+		if _, ok := nodes[branch]; !ok {
+			branches = append(branches, branch)
+		}
+		nodes[branch] = append(nodes[branch], fmt.Sprintf("\t\t\"%s\" [%s];\n", task.WBS, strings.Join(attrs, ", ")))
+	}
+
+	out := bytes.NewBufferString("")
+	out.WriteString("digraph PERT {\n")
+	out.WriteString("\trankdir=LR;\n")
+	out.WriteString("\tnode [shape=record, style=filled, fillcolor=white];\n")
+	out.WriteString("\tStart [shape=circle, label=\"Start\\n0.0\"];\n")
+	out.WriteString(fmt.Sprintf("\tFinish [shape=doublecircle, label=\"Finish\\n%0.1f ± %0.2f\"];\n", duration, stdDev))
+	for _, branch := range branches {
+		out.WriteString(fmt.Sprintf("\tsubgraph \"cluster_%s\" {\n", branch))
+		out.WriteString(fmt.Sprintf("\t\tlabel=\"%s\";\n", branch))
+		out.WriteString("\t\trank=same;\n")
+		for _, node := range nodes[branch] {
+			out.WriteString(node)
+		}
+		out.WriteString("\t}\n")
+	}
+	for _, link := range links {
+		var attrs []string
+		if link.Critical {
+			attrs = append(attrs, "color=red", "penwidth=3")
+		}
+		if link.Label != "" {
+			attrs = append(attrs, fmt.Sprintf(`label="%s"`, link.Label))
+		}
+		if len(attrs) > 0 {
+			out.WriteString(fmt.Sprintf("\t\"%s\" -> \"%s\" [%s];\n", link.From, link.To, strings.Join(attrs, ", ")))
+		} else {
+			out.WriteString(fmt.Sprintf("\t\"%s\" -> \"%s\";\n", link.From, link.To))
+		}
+	}
+	out.WriteString("}\n")
+	return out.String()
+}
@@ -0,0 +1,54 @@
+package render
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"wbspert/pkg/wbs"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func Test_pertDot(t *testing.T) {
+	sheets := []wbs.Sheet{
+		{WBS: "1.1", Title: "Design {draft}", Duration: 2, Status: "Done"},
+		{WBS: "1.2", Title: "Build", Parents: "1.1", Duration: 3, Status: "In Progress"},
+		{WBS: "2.1", Title: "Docs | guides", Parents: "1.1", Duration: 1, Status: "Waiting"},
+		{WBS: "2.2", Title: "Release", Parents: "1.2, 2.1", Optimistic: 1, MostLikely: 1, Pessimistic: 4, Status: "Blocked"},
+	}
+	tests := []struct {
+		name   string
+		golden string
+		opts   *Options
+	}{
+		{name: "Full network", golden: "pert.dot", opts: &Options{Level: 2, Format: DotFormat}},
+		{name: "Critical only", golden: "pert_critical.dot", opts: &Options{Level: 2, Format: DotFormat, CriticalOnly: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks := pertTasks(sheets, tt.opts)
+			schedule, duration := wbs.ComputeSchedule(tasks)
+			for i := range tasks {
+				tasks[i].Schedule = *schedule[tasks[i].WBS]
+			}
+			links := pertLinks(tasks, schedule, duration, tt.opts)
+			got := pertDot(tasks, links, duration, 0.5, tt.opts)
+
+			golden := filepath.Join("testdata", tt.golden)
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Errorf("pertDot() = %v, want %v", got, string(want))
+			}
+		})
+	}
+}
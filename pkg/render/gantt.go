@@ -0,0 +1,247 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"wbspert/pkg/wbs"
+)
+
+// ganttTitle replaces the brackets PlantUML uses for task names
+var ganttTitle = strings.NewReplacer("[", "(", "]", ")")
+
+// ganttDays returns the task's expected duration as the whole
+// number of days PlantUML needs for a Gantt task
+func ganttDays(s *wbs.Sheet, opts *Options) int {
+	days := int(math.Ceil(workingDays(s.Expected(), opts)))
+	if days < 1 {
+		days = 1
+	}
+	return days
+}
+
+// workingDays converts a schedule offset to days using the calendar's
+// duration unit.  Without a calendar offsets are already days.
+func workingDays(offset float32, opts *Options) float64 {
+	if opts.Calendar != nil {
+		return opts.Calendar.Days(offset)
+	}
+	return float64(offset)
+}
+
+// closedDays returns the weekdays that aren't worked and the sorted
+// holidays of the calendar
+func closedDays(cal *wbs.Calendar) ([]string, []string) {
+	var weekdays []string
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		worked := false
+		for _, w := range cal.Weekdays {
+			worked = worked || w == day
+		}
+		if !worked {
+			weekdays = append(weekdays, strings.ToLower(day.String()))
+		}
+	}
+	var holidays []string
+	for day := range cal.Holidays {
+		holidays = append(holidays, day)
+	}
+	sort.Strings(holidays)
+	return weekdays, holidays
+}
+
+// ganttTasks returns the tasks shown in the Gantt chart along
+// with their computed schedule
+func ganttTasks(sheets []wbs.Sheet, opts *Options) ([]wbs.Sheet, map[string]*wbs.Schedule) {
+	var tasks []wbs.Sheet
+	for _, sheet := range orderTasks(sheets, opts) {
+		if opts.ActiveOnly && sheet.IsCompleted() {
+			continue
+		}
+		tasks = append(tasks, sheet)
+	}
+	schedule, _ := scheduleTasks(tasks, opts)
+	return tasks, schedule
+}
+
+// projectStart parses the configured project start date.  With a
+// calendar it is the calendar's first working day.  If no date is
+// configured the zero time is returned.
+func projectStart(opts *Options) (time.Time, error) {
+	if opts.Calendar != nil {
+		return opts.Calendar.StartDate(0), nil
+	}
+	if opts.Start == "" {
+		return time.Time{}, nil
+	}
+	start, err := time.Parse("2006-01-02", opts.Start)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid start date %s: %s", opts.Start, err)
+	}
+	return start, nil
+}
+
+// ganttDriver returns the dependency that sets the task's start:
+// the one whose parent in the schedule allows the latest start
+func ganttDriver(task *wbs.Sheet, schedule map[string]*wbs.Schedule, opts *Options) (wbs.Dependency, bool) {
+	var driver wbs.Dependency
+	var latest float32
+	found := false
+	for _, d := range task.GetDependencies() {
+		parent, ok := schedule[d.Task]
+		if !ok {
+			continue
+		}
+		start := ganttAnchor(d, parent) + d.InUnits(opts.Calendar).Lag
+		if d.Type == wbs.FinishToFinish || d.Type == wbs.StartToFinish {
+			start -= task.Schedule.EF - task.Schedule.ES
+		}
+		if !found || start > latest {
+			driver, latest, found = d, start, true
+		}
+	}
+	return driver, found
+}
+
+// ganttAnchor returns the end of the parent a dependency hangs from
+func ganttAnchor(d wbs.Dependency, parent *wbs.Schedule) float32 {
+	if d.Type == wbs.StartToStart || d.Type == wbs.StartToFinish {
+		return parent.ES
+	}
+	return parent.EF
+}
+
+// ganttConstraint returns the PlantUML timing that ties the task to
+// the parent of its driving dependency.  Start-to-start and
+// finish-to-start dependencies place the task's start, the others
+// its end.  A milestone happens at the time either gives.
+func ganttConstraint(task *wbs.Sheet, d wbs.Dependency, parent *wbs.Schedule, opts *Options) string {
+	verb, offset := "starts", task.Schedule.ES
+	if d.Type == wbs.FinishToFinish || d.Type == wbs.StartToFinish {
+		verb, offset = "ends", task.Schedule.EF
+	}
+	if task.IsMilestone() {
+		verb = "happens"
+	}
+	end := "end"
+	if d.Type == wbs.StartToStart || d.Type == wbs.StartToFinish {
+		end = "start"
+	}
+	lag := int(math.Round(workingDays(offset-ganttAnchor(d, parent), opts)))
+	switch {
+	case lag > 0:
+		return fmt.Sprintf("%s %d days after [%s]'s %s", verb, lag, d.Task, end)
+	case lag < 0:
+		return fmt.Sprintf("%s %d days before [%s]'s %s", verb, -lag, d.Task, end)
+	}
+	return fmt.Sprintf("%s at [%s]'s %s", verb, d.Task, end)
+}
+
+// ganttStart returns the PlantUML timing of a task no dependency
+// ties to another bar.  Tasks at the project start need none, but a
+// milestone is always given one.
+func ganttStart(task *wbs.Sheet, start time.Time, opts *Options) string {
+	verb := "starts"
+	if task.IsMilestone() {
+		verb = "happens"
+	}
+	lag := int(math.Round(workingDays(task.Schedule.ES, opts)))
+	switch {
+	case lag <= 0 && !task.IsMilestone():
+		return ""
+	case task.Schedule.Dates != nil:
+		return fmt.Sprintf("%s %s", verb, task.Schedule.Dates.ES.Format(wbs.DateFormat))
+	case !start.IsZero():
+		return fmt.Sprintf("%s %s", verb, start.AddDate(0, 0, lag).Format("2006-01-02"))
+	}
+	return fmt.Sprintf("%s D+%d", verb, lag)
+}
+
+// ganttBars returns the schedule of the tasks drawn as bars or
+// milestones, leaving out the summary tasks shown as separators
+func ganttBars(tasks []wbs.Sheet, schedule map[string]*wbs.Schedule) map[string]*wbs.Schedule {
+	bars := make(map[string]*wbs.Schedule)
+	for _, task := range tasks {
+		if sched, ok := schedule[task.WBS]; ok && !task.IsSummary() {
+			bars[task.WBS] = sched
+		}
+	}
+	return bars
+}
+
+// ganttPlantUML builds the PlantUML Gantt chart for the tasks.
+// Each task is tied to the parent whose dependency sets its start,
+// with any lag or delay from resource leveling.  Milestones are
+// drawn as diamonds and summary tasks as separators.
+func ganttPlantUML(sheets []wbs.Sheet, opts *Options) (string, error) {
+	tasks, schedule := ganttTasks(sheets, opts)
+	start, err := projectStart(opts)
+	if err != nil {
+		return "", err
+	}
+
+	out := bytes.NewBufferString("")
+	out.WriteString("@startgantt\n")
+	if !start.IsZero() {
+		out.WriteString(fmt.Sprintf("Project starts %s\n", start.Format("2006-01-02")))
+	}
+	if opts.Calendar != nil {
+		weekdays, holidays := closedDays(opts.Calendar)
+		for _, day := range weekdays {
+			out.WriteString(fmt.Sprintf("%s are closed\n", day))
+		}
+		for _, day := range holidays {
+			out.WriteString(fmt.Sprintf("%s is closed\n", day))
+		}
+	}
+	bars := ganttBars(tasks, schedule)
+	for _, task := range tasks {
+		if task.IsSummary() {
+			out.WriteString(fmt.Sprintf("-- %s --\n", ganttTitle.Replace(task.Label())))
+			continue
+		}
+		title := ganttTitle.Replace(task.Title)
+		driver, driven := ganttDriver(&task, bars, opts)
+		var timing string
+		if driven {
+			timing = ganttConstraint(&task, driver, bars[driver.Task], opts)
+		} else {
+			timing = ganttStart(&task, start, opts)
+		}
+		if task.IsMilestone() {
+			out.WriteString(fmt.Sprintf("[%s: %s] as [%s] %s\n", task.WBS, title, task.WBS, timing))
+		} else {
+			out.WriteString(fmt.Sprintf("[%s: %s] as [%s] lasts %d days\n", task.WBS, title, task.WBS, ganttDays(&task, opts)))
+		}
+		if color := task.StatusColor(); color != "" {
+			out.WriteString(fmt.Sprintf("[%s] is colored in %s\n", task.WBS, color))
+		}
+		if !task.IsMilestone() && timing != "" {
+			out.WriteString(fmt.Sprintf("[%s] %s\n", task.WBS, timing))
+		}
+	}
+	out.WriteString("\nfooter\nAs of %date()\nend footer\n")
+	out.WriteString("@endgantt\n")
+	return out.String(), nil
+}
+
+// Gantt writes the Gantt chart of the tasks in the configured format
+func Gantt(w io.Writer, sheets []wbs.Sheet, opts *Options) error {
+	var diagram string
+	var err error
+	if opts.Format == MermaidFormat {
+		diagram, err = ganttMermaid(sheets, opts)
+	} else {
+		diagram, err = ganttPlantUML(sheets, opts)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, diagram)
+	return err
+}
@@ -0,0 +1,76 @@
+package render
+
+import (
+	"testing"
+
+	"wbspert/pkg/wbs"
+)
+
+func Test_ganttPlantUML(t *testing.T) {
+	sheets := []wbs.Sheet{
+		{WBS: "1.1", Title: "Design [draft]", Duration: 2, Status: "Done"},
+		{WBS: "1.2", Title: "Build", Parents: "1.1", Duration: 2.5, Status: "In Progress"},
+		{WBS: "1.3", Title: "Review", Parents: "1.1", Duration: 1},
+		{WBS: "1.4", Title: "Ship", Parents: "1.3, 1.2", Duration: 0},
+	}
+	want := `@startgantt
+Project starts 2024-03-04
+[1.1: Design (draft)] as [1.1] lasts 2 days
+[1.1] is colored in Thistle
+[1.2: Build] as [1.2] lasts 3 days
+[1.2] is colored in DarkSeaGreen
+[1.2] starts at [1.1]'s end
+[1.3: Review] as [1.3] lasts 1 days
+[1.3] starts at [1.1]'s end
+[1.4: Ship] as [1.4] lasts 1 days
+[1.4] starts at [1.2]'s end
+
+footer
+As of %date()
+end footer
+@endgantt
+`
+	got, err := ganttPlantUML(sheets, &Options{Start: "2024-03-04"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("ganttPlantUML() = %v, want %v", got, want)
+	}
+}
+
+func Test_ganttPlantUMLDependencyTypes(t *testing.T) {
+	sheets := []wbs.Sheet{
+		{WBS: "1", Title: "Dig", Duration: 4},
+		{WBS: "2", Title: "Pour", Parents: "1SS+2", Duration: 3},
+		{WBS: "3", Title: "Inspect", Parents: "1FF+1", Duration: 2},
+		{WBS: "4", Title: "Close", Parents: "2, 3FS-1", Duration: 1},
+	}
+	want := `@startgantt
+[1: Dig] as [1] lasts 4 days
+[2: Pour] as [2] lasts 3 days
+[2] starts 2 days after [1]'s start
+[3: Inspect] as [3] lasts 2 days
+[3] ends 1 days after [1]'s end
+[4: Close] as [4] lasts 1 days
+[4] starts at [2]'s end
+
+footer
+As of %date()
+end footer
+@endgantt
+`
+	got, err := ganttPlantUML(sheets, &Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("ganttPlantUML() = %v, want %v", got, want)
+	}
+}
+
+func Test_projectStart(t *testing.T) {
+	if _, err := projectStart(&Options{Start: "03/04/2024"}); err == nil {
+		t.Errorf("projectStart() expected an error for an invalid date")
+	}
+}
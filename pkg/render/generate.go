@@ -0,0 +1,111 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"ghprojects/projects"
+
+	"wbspert/pkg/wbs"
+)
+
+// Charts selects the outputs that Generate writes
+type Charts struct {
+	PERT        bool
+	WBS         bool
+	Gantt       bool
+	Table       bool
+	Kanban      bool
+	BugList     bool
+	EpicList    bool
+	EpicStories bool
+	Simulation  bool
+	Load        bool
+	Leveling    bool
+	Milestones  bool
+}
+
+// Output is the destination for generated content.  When Embed is
+// set the content is placed between the embed tags of the file at
+// Path, otherwise it is written to Writer.
+type Output struct {
+	Writer io.Writer
+	Path   string
+	Embed  bool
+	// Tag replaces the chart's embed tag when set
+	Tag string
+	// Format replaces the diagram format of the options when set
+	Format string
+}
+
+// write sends the rendered content to the output.  Embedded diagrams
+// are wrapped in a code fence for their format.
+func (o Output) write(tag string, fenced bool, opts *Options, renderer func(io.Writer, *Options) error) error {
+	if o.Format != "" && o.Format != opts.Format {
+		override := *opts
+		override.Format = o.Format
+		opts = &override
+	}
+	if !o.Embed {
+		return renderer(o.Writer, opts)
+	}
+	if o.Tag != "" {
+		tag = o.Tag
+	}
+	buf := bytes.NewBufferString("")
+	if err := renderer(buf, opts); err != nil {
+		return err
+	}
+	text := buf.String()
+	if fenced {
+		text = Fence(opts.Format, text)
+	}
+	return EmbedFile(o.Path, text, tag)
+}
+
+// Generate writes every selected chart for the tasks.  Targets maps
+// a chart's embed tag to its own output; charts without a target are
+// written to out.  The board is only needed for the Kanban table.
+func Generate(out Output, targets map[string]Output, charts Charts, sheets []wbs.Sheet, board *projects.Board, opts *Options) error {
+	type generator struct {
+		enabled bool
+		tag     string
+		fenced  bool
+		render  func(io.Writer, *Options) error
+	}
+	generators := []generator{
+		{charts.PERT, PertTag, true, func(w io.Writer, o *Options) error { return PertChart(w, sheets, o) }},
+		{charts.WBS, WBSTag, true, func(w io.Writer, o *Options) error { return WBS(w, sheets, o) }},
+		{charts.Gantt, GanttTag, true, func(w io.Writer, o *Options) error { return Gantt(w, sheets, o) }},
+		{charts.Table, WBSTableTag, false, func(w io.Writer, o *Options) error { return WBSTable(w, sheets, o) }},
+		{charts.Kanban, KanbanTag, false, func(w io.Writer, o *Options) error {
+			if board == nil {
+				return fmt.Errorf("the kanban table needs a GitHub project")
+			}
+			return Kanban(w, board, o)
+		}},
+		{charts.BugList, BugTag, false, func(w io.Writer, o *Options) error { return BugList(w, sheets, o) }},
+		{charts.EpicList, EpicTag, false, func(w io.Writer, o *Options) error { return EpicList(w, sheets, o) }},
+		{charts.Simulation, SimTag, false, func(w io.Writer, o *Options) error { return Simulate(w, sheets, o) }},
+		{charts.Load, LoadTag, false, func(w io.Writer, o *Options) error { return LoadReport(w, sheets, o) }},
+		{charts.Leveling, LevelingTag, false, func(w io.Writer, o *Options) error { return LevelingReport(w, sheets, o) }},
+		{charts.Milestones, MilestoneTag, false, func(w io.Writer, o *Options) error { return Milestones(w, sheets, o) }},
+	}
+	for _, gen := range generators {
+		if !gen.enabled {
+			continue
+		}
+		dst := out
+		if target, ok := targets[gen.tag]; ok {
+			dst = target
+		}
+		if err := dst.write(gen.tag, gen.fenced, opts, gen.render); err != nil {
+			return err
+		}
+	}
+	if charts.EpicStories {
+		return EpicStories(sheets, opts)
+	}
+	return nil
+}
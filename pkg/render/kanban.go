@@ -0,0 +1,86 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"ghprojects/projects"
+
+	"wbspert/pkg/wbs"
+)
+
+func FilterCards(columns []*projects.BoardColumn, filter string) []*projects.BoardColumn {
+	for _, column := range columns {
+		newCards := make([]*projects.Card, 0)
+		for _, card := range column.Cards {
+			if !(wbs.InArray(filter, card.Labels) || card.Fields["Type"] == filter) {
+				continue
+			}
+			newCards = append(newCards, card)
+		}
+		column.Cards = newCards
+	}
+	return columns
+}
+
+// Kanban writes a markdown table with one column per board column.
+// Cards whose status is complete in the options' vocabulary are
+// struck through, or left out with ActiveOnly.
+func Kanban(w io.Writer, board *projects.Board, opts *Options) error {
+	var rows [][]string
+	out := bytes.NewBufferString("")
+	if opts.Column != "" && opts.Column != "Status" {
+		board.SetCards(opts.Column)
+	}
+
+	if len(opts.Filter) > 0 {
+		FilterCards(board.Columns, opts.Filter)
+	}
+	maxRows := determineRows(board.Columns)
+	rows = make([][]string, maxRows)
+	for i := range rows {
+		rows[i] = make([]string, len(board.Columns))
+	}
+
+	for _, col := range board.Columns {
+		fmt.Fprintf(out, "| %s ", col.Name)
+	}
+	fmt.Fprintln(out, "|")
+	for i := 0; i < len(board.Columns); i++ {
+		fmt.Fprint(out, "| --- ")
+	}
+	fmt.Fprintln(out, "|")
+
+	for colNum, curCol := range board.Columns {
+		for colRow, card := range curCol.Cards {
+			complete := ""
+			if style := wbs.LookupStatus(opts.Statuses, card.Status); style != nil && style.Complete {
+				if opts.ActiveOnly {
+					continue
+				}
+				complete = "~~"
+			}
+
+			rows[colRow][colNum] = fmt.Sprintf("%s%s%s", complete, card.Title, complete)
+		}
+	}
+	for _, row := range rows {
+		for _, col := range row {
+			fmt.Fprintf(out, "| %s ", col)
+		}
+		fmt.Fprintln(out, "|")
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+func determineRows(cols []*projects.BoardColumn) int {
+	var maxRows int
+	for _, column := range cols {
+		if len(column.Cards) > maxRows {
+			maxRows = len(column.Cards)
+		}
+	}
+	return maxRows
+}
@@ -0,0 +1,52 @@
+package render
+
+import (
+	"bytes"
+	"testing"
+
+	"ghprojects/projects"
+
+	"wbspert/pkg/wbs"
+)
+
+func TestKanban(t *testing.T) {
+	board := func() *projects.Board {
+		return &projects.Board{Columns: []*projects.BoardColumn{
+			{Name: "Todo", Cards: []*projects.Card{{Title: "Build", Status: "Todo"}}},
+			{Name: "Shipped", Cards: []*projects.Card{{Title: "Design", Status: "Shipped"}}},
+		}}
+	}
+	statuses := []wbs.StatusStyle{{Category: "complete", Statuses: []string{"shipped"}, Complete: true}}
+	tests := []struct {
+		name string
+		opts *Options
+		want string
+	}{
+		{
+			name: "Default statuses",
+			opts: &Options{Column: "Status"},
+			want: "| Todo | Shipped |\n| --- | --- |\n| Build | Design |\n",
+		},
+		{
+			name: "Custom complete status",
+			opts: &Options{Column: "Status", Statuses: statuses},
+			want: "| Todo | Shipped |\n| --- | --- |\n| Build | ~~Design~~ |\n",
+		},
+		{
+			name: "Active only",
+			opts: &Options{Column: "Status", Statuses: statuses, ActiveOnly: true},
+			want: "| Todo | Shipped |\n| --- | --- |\n| Build |  |\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := bytes.NewBufferString("")
+			if err := Kanban(out, board(), tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("Kanban() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"wbspert/pkg/wbs"
+)
+
+const mermaidCritical = "stroke:Red,stroke-width:3px"
+
+// mermaidID converts a WBS ID into a Mermaid node ID, since
+// Mermaid doesn't allow dots in IDs
+func mermaidID(wbs string) string {
+	if wbs == "Start" || wbs == "Finish" {
+		return wbs
+	}
+	return "T" + strings.ReplaceAll(wbs, ".", "_")
+}
+
+// mermaidText removes the characters Mermaid treats as syntax
+// from a task title
+var mermaidText = strings.NewReplacer(`"`, "", "[", "(", "]", ")", ":", " -", ";", ",", "#", "")
+
+// pertMermaid renders the PERT network as a Mermaid flowchart
+func pertMermaid(tasks []wbs.Sheet, links []pertLink, duration float32, stdDev float64, opts *Options) string {
+	out := bytes.NewBufferString("")
+	out.WriteString("flowchart LR\n")
+	out.WriteString("    Start((Start))\n")
+	out.WriteString(fmt.Sprintf("    Finish((\"Finish<br/>Expected: %0.1f<br/>Std dev: %0.2f\"))\n", duration, stdDev))
+	for _, task := range tasks {
+		sched := task.Schedule
+		if opts.CriticalOnly && !sched.Critical {
+			continue
+		}
+		id := mermaidID(task.WBS)
+		if task.IsMilestone() {
+			out.WriteString(fmt.Sprintf("    %s{\"%s: %s<br/>Status: %s<br/>%s<br/>Slack: %0.1f\"}\n",
+				id, task.WBS, mermaidText.Replace(task.Title), task.Status, milestoneDay(&task), sched.Slack))
+		} else {
+			out.WriteString(fmt.Sprintf("    %s[\"%s: %s<br/>Status: %s<br/>ES: %0.1f | EF: %0.1f<br/>Duration: %0.1f<br/>TE: %0.1f | Var: %0.2f<br/>LS: %0.1f | LF: %0.1f<br/>Slack: %0.1f\"]\n",
+				id, task.WBS, mermaidText.Replace(task.Title), task.Status,
+				sched.ES, sched.EF, task.Duration, task.Expected(), task.Variance(), sched.LS, sched.LF, sched.Slack))
+		}
+		var style []string
+		if color := task.StatusColor(); color != "" {
+			style = append(style, "fill:"+color)
+		}
+		if sched.Critical {
+			style = append(style, mermaidCritical)
+		}
+		if len(style) > 0 {
+			out.WriteString(fmt.Sprintf("    style %s %s\n", id, strings.Join(style, ",")))
+		}
+	}
+	var critical []string
+	for i, link := range links {
+		arrow := "-->"
+		if link.Critical {
+			arrow = "==>"
+			critical = append(critical, strconv.Itoa(i))
+		}
+		if link.Label != "" {
+			arrow += "|" + link.Label + "|"
+		}
+		out.WriteString(fmt.Sprintf("    %s %s %s\n", mermaidID(link.From), arrow, mermaidID(link.To)))
+	}
+	if len(critical) > 0 {
+		out.WriteString(fmt.Sprintf("    linkStyle %s %s\n", strings.Join(critical, ","), mermaidCritical))
+	}
+	return out.String()
+}
+
+// wbsMermaid renders the work breakdown structure as a Mermaid
+// mindmap.  Each task is indented by its level below the root.
+func wbsMermaid(sheets []wbs.Sheet, opts *Options) string {
+	out := bytes.NewBufferString("")
+	out.WriteString("mindmap\n")
+	out.WriteString("  root((Project))\n")
+	for _, sheet := range sheets {
+		if opts.ActiveOnly && sheet.IsCompleted() {
+			continue
+		}
+		label := sheet.WBS
+		if sheet.Title != "" {
+			label += ": " + mermaidText.Replace(sheet.Title)
+		}
+		out.WriteString(fmt.Sprintf("%s%s[%s]\n", strings.Repeat("  ", sheet.GetLevel()+1), mermaidID(sheet.WBS), label))
+	}
+	return out.String()
+}
+
+// ganttMermaid renders the tasks as a Mermaid Gantt chart.  Tasks
+// without parents begin on the project start date, or today if no
+// start date is configured.  Tasks delayed by resource leveling or
+// with a typed or lagged dependency are given their start date.
+// Summary tasks start a new section.
+func ganttMermaid(sheets []wbs.Sheet, opts *Options) (string, error) {
+	tasks, schedule := ganttTasks(sheets, opts)
+	start, err := projectStart(opts)
+	if err != nil {
+		return "", err
+	}
+	if start.IsZero() {
+		start = time.Now()
+	}
+
+	out := bytes.NewBufferString("")
+	out.WriteString("gantt\n")
+	out.WriteString("    dateFormat YYYY-MM-DD\n")
+	if opts.Calendar != nil {
+		weekdays, holidays := closedDays(opts.Calendar)
+		if excludes := append(weekdays, holidays...); len(excludes) > 0 {
+			out.WriteString(fmt.Sprintf("    excludes %s\n", strings.Join(excludes, ", ")))
+		}
+	}
+	bars := ganttBars(tasks, schedule)
+	section := false
+	for _, task := range tasks {
+		if task.IsSummary() {
+			out.WriteString(fmt.Sprintf("    section %s\n", mermaidText.Replace(task.Label())))
+			section = true
+			continue
+		}
+		if !section {
+			out.WriteString("    section Project\n")
+			section = true
+		}
+		var fields []string
+		if task.IsMilestone() {
+			fields = append(fields, "milestone")
+		}
+		if task.IsCompleted() {
+			fields = append(fields, "done")
+		} else if task.IsActive() {
+			fields = append(fields, "active")
+		}
+		if task.Schedule.Critical {
+			fields = append(fields, "crit")
+		}
+		fields = append(fields, mermaidID(task.WBS))
+		var after []string
+		fixed := task.Schedule.Shift > wbs.CriticalSlack
+		for _, d := range task.GetDependencies() {
+			if _, ok := bars[d.Task]; ok {
+				after = append(after, mermaidID(d.Task))
+				fixed = fixed || d.Label() != ""
+			} else if _, ok := schedule[d.Task]; ok {
+				fixed = true
+			}
+		}
+		if fixed && task.Schedule.Dates != nil {
+			fields = append(fields, task.Schedule.Dates.ES.Format(wbs.DateFormat))
+		} else if fixed {
+			fields = append(fields, start.AddDate(0, 0, int(math.Round(float64(task.Schedule.ES)))).Format("2006-01-02"))
+		} else if len(after) > 0 {
+			fields = append(fields, "after "+strings.Join(after, " "))
+		} else {
+			fields = append(fields, start.Format("2006-01-02"))
+		}
+		if task.IsMilestone() {
+			fields = append(fields, "0d")
+		} else {
+			fields = append(fields, fmt.Sprintf("%dd", ganttDays(&task, opts)))
+		}
+		out.WriteString(fmt.Sprintf("    %s %s :%s\n", task.WBS, mermaidText.Replace(task.Title), strings.Join(fields, ", ")))
+	}
+	return out.String(), nil
+}
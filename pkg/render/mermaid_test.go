@@ -0,0 +1,73 @@
+package render
+
+import (
+	"testing"
+
+	"wbspert/pkg/wbs"
+)
+
+var mermaidSheets = []wbs.Sheet{
+	{WBS: "1", Title: "Project [x]", Status: "In Progress"},
+	{WBS: "1.1", Title: "Design", Duration: 2, Status: "Done"},
+	{WBS: "1.2", Title: "Build: core", Parents: "1.1", Duration: 3, Status: "In Progress"},
+	{WBS: "1.3", Title: "Docs", Parents: "1.1", Duration: 1, Status: "Waiting"},
+}
+
+func Test_pertMermaid(t *testing.T) {
+	opts := &Options{Level: 2, Format: MermaidFormat}
+	tasks := pertTasks(mermaidSheets, opts)
+	schedule, duration := wbs.ComputeSchedule(tasks)
+	for i := range tasks {
+		tasks[i].Schedule = *schedule[tasks[i].WBS]
+	}
+	links := pertLinks(tasks, schedule, duration, opts)
+	want := `flowchart LR
+    Start((Start))
+    Finish(("Finish<br/>Expected: 5.0<br/>Std dev: 0.00"))
+    T1_1["1.1: Design<br/>Status: Done<br/>ES: 0.0 | EF: 2.0<br/>Duration: 2.0<br/>TE: 2.0 | Var: 0.00<br/>LS: 0.0 | LF: 2.0<br/>Slack: 0.0"]
+    style T1_1 fill:Thistle,stroke:Red,stroke-width:3px
+    T1_2["1.2: Build - core<br/>Status: In Progress<br/>ES: 2.0 | EF: 5.0<br/>Duration: 3.0<br/>TE: 3.0 | Var: 0.00<br/>LS: 2.0 | LF: 5.0<br/>Slack: 0.0"]
+    style T1_2 fill:DarkSeaGreen,stroke:Red,stroke-width:3px
+    T1_3["1.3: Docs<br/>Status: Waiting<br/>ES: 2.0 | EF: 3.0<br/>Duration: 1.0<br/>TE: 1.0 | Var: 0.00<br/>LS: 4.0 | LF: 5.0<br/>Slack: 2.0"]
+    style T1_3 fill:Pink
+    Start ==> T1_1
+    T1_1 ==> T1_2
+    T1_1 --> T1_3
+    T1_2 ==> Finish
+    T1_3 --> Finish
+    linkStyle 0,1,3 stroke:Red,stroke-width:3px
+`
+	if got := pertMermaid(tasks, links, duration, 0, opts); got != want {
+		t.Errorf("pertMermaid() = %v, want %v", got, want)
+	}
+}
+
+func Test_wbsMermaid(t *testing.T) {
+	want := `mindmap
+  root((Project))
+    T1[1: Project (x)]
+      T1_1[1.1: Design]
+      T1_2[1.2: Build - core]
+      T1_3[1.3: Docs]
+`
+	if got := wbsMermaid(mermaidSheets, &Options{}); got != want {
+		t.Errorf("wbsMermaid() = %v, want %v", got, want)
+	}
+}
+
+func Test_ganttMermaid(t *testing.T) {
+	want := `gantt
+    dateFormat YYYY-MM-DD
+    section Project
+    1.1 Design :done, crit, T1_1, 2024-03-04, 2d
+    1.2 Build - core :active, crit, T1_2, after T1_1, 3d
+    1.3 Docs :T1_3, after T1_1, 1d
+`
+	got, err := ganttMermaid(mermaidSheets[1:], &Options{Start: "2024-03-04"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("ganttMermaid() = %v, want %v", got, want)
+	}
+}
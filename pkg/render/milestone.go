@@ -0,0 +1,82 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"wbspert/pkg/wbs"
+)
+
+// milestoneDay returns the calendar date a scheduled milestone is
+// reached, or its offset from the start without a calendar
+func milestoneDay(task *wbs.Sheet) string {
+	if dates := task.Schedule.Dates; dates != nil {
+		return "Date: " + dates.EF.Format(wbs.DateFormat)
+	}
+	return fmt.Sprintf("Day: %0.1f", task.Schedule.ES)
+}
+
+// forecastDate returns the date a scheduled milestone is reached:
+// its calendar date, or its offset from the project start.  Either
+// way it is the day the milestone starts and finishes.  When neither
+// is known the zero time is returned.
+func forecastDate(task *wbs.Sheet, start time.Time, opts *Options) time.Time {
+	if dates := task.Schedule.Dates; dates != nil {
+		return dates.EF
+	}
+	if start.IsZero() {
+		return time.Time{}
+	}
+	days := int(math.Floor(workingDays(task.Schedule.ES, opts) + wbs.CriticalSlack))
+	return start.AddDate(0, 0, days)
+}
+
+// Milestones writes a markdown table of the milestones with their
+// target date, forecast date and how far the forecast slips past
+// the target
+func Milestones(w io.Writer, sheets []wbs.Sheet, opts *Options) error {
+	start, err := projectStart(opts)
+	if err != nil {
+		return err
+	}
+	tasks, _ := ganttTasks(sheets, opts)
+
+	out := bytes.NewBufferString("")
+	out.WriteString("| WBS | Milestone | Status | Target | Forecast | Slip |\n")
+	out.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+	found := 0
+	for _, task := range tasks {
+		if !task.IsMilestone() {
+			continue
+		}
+		found++
+		target, err := task.TargetDate()
+		if err != nil {
+			return err
+		}
+		forecast := forecastDate(&task, start, opts)
+		var targetText, forecastText, slip string
+		if !target.IsZero() {
+			targetText = target.Format(wbs.DateFormat)
+		}
+		if forecast.IsZero() {
+			forecastText = fmt.Sprintf("Day %0.1f", task.Schedule.ES)
+		} else {
+			forecastText = forecast.Format(wbs.DateFormat)
+		}
+		if !target.IsZero() && !forecast.IsZero() {
+			slip = fmt.Sprintf("%+d days", int(math.Round(forecast.Sub(target).Hours()/24)))
+		}
+		out.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n",
+			task.WBS, task.Title, task.Status, targetText, forecastText, slip))
+	}
+	if found == 0 {
+		out.Reset()
+		out.WriteString("No milestones\n")
+	}
+	_, err = io.WriteString(w, out.String())
+	return err
+}
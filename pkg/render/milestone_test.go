@@ -0,0 +1,137 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"wbspert/pkg/wbs"
+)
+
+func TestMilestones(t *testing.T) {
+	sheets := []wbs.Sheet{
+		{WBS: "1", Title: "Design", Duration: 2},
+		{WBS: "2", Title: "Sign off", Type: "milestone", Parents: "1", Target: "2024-03-05", Status: "Waiting"},
+		{WBS: "3", Title: "Build", Duration: 3, Parents: "2"},
+		{WBS: "4", Title: "Launch", Type: "milestone", Parents: "3", Target: "2024-03-12"},
+	}
+	header := "| WBS | Milestone | Status | Target | Forecast | Slip |\n" +
+		"| --- | --- | --- | --- | --- | --- |\n"
+	tests := []struct {
+		name   string
+		sheets []wbs.Sheet
+		opts   *Options
+		want   string
+	}{
+		{
+			name:   "Start date",
+			sheets: sheets,
+			opts:   &Options{Start: "2024-03-04"},
+			want: header +
+				"| 2 | Sign off | Waiting | 2024-03-05 | 2024-03-06 | +1 days |\n" +
+				"| 4 | Launch |  | 2024-03-12 | 2024-03-09 | -3 days |\n",
+		},
+		{
+			name:   "Calendar",
+			sheets: sheets,
+			opts:   &Options{Calendar: wbs.NewCalendar(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC))},
+			want: header +
+				"| 2 | Sign off | Waiting | 2024-03-05 | 2024-03-06 | +1 days |\n" +
+				"| 4 | Launch |  | 2024-03-12 | 2024-03-11 | -1 days |\n",
+		},
+		{
+			name:   "Offsets",
+			sheets: sheets,
+			opts:   &Options{},
+			want: header +
+				"| 2 | Sign off | Waiting | 2024-03-05 | Day 2.0 |  |\n" +
+				"| 4 | Launch |  | 2024-03-12 | Day 5.0 |  |\n",
+		},
+		{
+			name:   "No milestones",
+			sheets: sheets[:1],
+			opts:   &Options{},
+			want:   "No milestones\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := bytes.NewBufferString("")
+			if err := Milestones(buf, tt.sheets, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Milestones() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ganttMilestones(t *testing.T) {
+	sheets := []wbs.Sheet{
+		{WBS: "1", Title: "Phase [one]", Type: "summary"},
+		{WBS: "1.1", Title: "Design", Duration: 2},
+		{WBS: "1.2", Title: "Sign off", Type: "milestone", Parents: "1.1", Status: "Done"},
+		{WBS: "1.3", Title: "Kickoff", Type: "milestone"},
+	}
+	want := `@startgantt
+Project starts 2024-03-04
+-- 1: Phase (one) --
+[1.1: Design] as [1.1] lasts 2 days
+[1.2: Sign off] as [1.2] happens at [1.1]'s end
+[1.2] is colored in Thistle
+[1.3: Kickoff] as [1.3] happens 2024-03-04
+
+footer
+As of %date()
+end footer
+@endgantt
+`
+	got, err := ganttPlantUML(sheets, &Options{Start: "2024-03-04"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("ganttPlantUML() = %v, want %v", got, want)
+	}
+
+	wantMermaid := `gantt
+    dateFormat YYYY-MM-DD
+    section 1 - Phase (one)
+    1.1 Design :crit, T1_1, 2024-03-04, 2d
+    1.2 Sign off :milestone, done, crit, T1_2, after T1_1, 0d
+    1.3 Kickoff :milestone, T1_3, 2024-03-04, 0d
+`
+	got, err = ganttMermaid(sheets, &Options{Start: "2024-03-04", Format: MermaidFormat})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != wantMermaid {
+		t.Errorf("ganttMermaid() = %v, want %v", got, wantMermaid)
+	}
+}
+
+func Test_pertMilestones(t *testing.T) {
+	sheets := []wbs.Sheet{
+		{WBS: "1", Title: "Design", Duration: 2, Status: "Todo"},
+		{WBS: "2", Title: "Sign off", Type: "milestone", Parents: "1", Status: "Todo"},
+	}
+	tests := map[string]string{
+		PlantUMLFormat: `map "◆ 2: Sign off" as 2`,
+		MermaidFormat:  `T2{"2: Sign off<br/>`,
+		DotFormat:      `shape=diamond`,
+		SVGFormat:      `<polygon points=`,
+	}
+	for format, want := range tests {
+		t.Run(format, func(t *testing.T) {
+			buf := bytes.NewBufferString("")
+			if err := PertChart(buf, sheets, &Options{Format: format}); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); !strings.Contains(got, want) {
+				t.Errorf("PertChart() = %v, want a milestone with %s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"wbspert/pkg/wbs"
+)
+
+const pertStart = `
+map Start {
+	Start => %0.1f
+}
+`
+const pertFinish = `
+map Finish {
+	Duration => %0.1f
+}
+`
+const criticalArrow = "-[#Red,bold]->"
+const pertFooter = `
+footer
+Expected duration: %0.1f | Std dev: %0.2f%s
+As of %%date()
+end footer
+`
+
+// pertTasks returns the tasks that make up the PERT network.
+// Summary tasks are kept at every level so the tasks that depend on
+// them are scheduled after the work under them, but they aren't
+// drawn.
+func pertTasks(sheets []wbs.Sheet, opts *Options) []wbs.Sheet {
+	var tasks []wbs.Sheet
+	for _, sheet := range orderTasks(sheets, opts) {
+		if strings.HasPrefix(sheet.WBS, "0.99") {
+			continue
+		}
+		if opts.ActiveOnly && sheet.IsCompleted() {
+			continue
+		}
+		if sheet.IsSummary() || sheet.GetLevel() >= opts.Level && !(sheet.Status == "") {
+			tasks = append(tasks, sheet)
+		}
+	}
+	return tasks
+}
+
+// pertNodes returns the tasks drawn in the PERT network, leaving
+// out the summary tasks
+func pertNodes(tasks []wbs.Sheet) []wbs.Sheet {
+	var nodes []wbs.Sheet
+	for _, task := range tasks {
+		if !task.IsSummary() {
+			nodes = append(nodes, task)
+		}
+	}
+	return nodes
+}
+
+// pertDependencies returns the dependencies drawn into the task: its
+// own and those of the summary tasks it is under
+func pertDependencies(task *wbs.Sheet, tasks []wbs.Sheet) []wbs.Dependency {
+	var deps []wbs.Dependency
+	for _, d := range task.GetDependencies() {
+		if d.Task != "" {
+			deps = append(deps, d)
+		}
+	}
+	for _, summary := range tasks {
+		if !summary.IsSummary() || !wbs.IsUnder(task.WBS, summary.WBS) {
+			continue
+		}
+		for _, d := range summary.GetDependencies() {
+			if d.Task != "" && !wbs.IsUnder(d.Task, summary.WBS) {
+				deps = append(deps, d)
+			}
+		}
+	}
+	if len(deps) == 0 {
+		deps = append(deps, wbs.Dependency{Type: wbs.FinishToStart})
+	}
+	return deps
+}
+
+// summaryEnds returns the drawn tasks under a summary that no other
+// task under it depends on.  Edges from the summary are drawn from
+// them.
+func summaryEnds(summary string, tasks []wbs.Sheet) []string {
+	var members, parents []string
+	for _, task := range tasks {
+		if wbs.IsUnder(task.WBS, summary) && !task.IsSummary() {
+			members = append(members, task.WBS)
+			parents = append(parents, task.GetParents()...)
+		}
+	}
+	var ends []string
+	for _, member := range members {
+		if !wbs.InArray(member, parents) {
+			ends = append(ends, member)
+		}
+	}
+	return ends
+}
+
+// pertLink is a dependency between two nodes of the PERT network
+type pertLink struct {
+	From     string
+	To       string
+	Critical bool
+	// Label is the dependency type and lag, empty for a plain
+	// finish-to-start dependency
+	Label string
+}
+
+// pertLinks returns the edges of the PERT network, including the
+// edges from Start and into Finish.  Only critical edges are
+// returned when the options ask for the critical path alone.
+// Summary tasks aren't drawn: an edge from one is drawn from each of
+// its last tasks.
+func pertLinks(tasks []wbs.Sheet, schedule map[string]*wbs.Schedule, duration float32, opts *Options) []pertLink {
+	summaries := make(map[string]bool)
+	for _, task := range tasks {
+		summaries[task.WBS] = task.IsSummary()
+	}
+	var allParents []string
+	var links []pertLink
+	for _, task := range tasks {
+		if task.IsSummary() {
+			continue
+		}
+		for _, d := range pertDependencies(&task, tasks) {
+			froms := []string{d.Task}
+			if summaries[d.Task] {
+				if froms = summaryEnds(d.Task, tasks); len(froms) == 0 {
+					froms = []string{""}
+				}
+			}
+			allParents = append(allParents, froms...)
+			critical := wbs.IsCriticalDependency(schedule, d.InUnits(opts.Calendar), task.WBS)
+			if opts.CriticalOnly && (!schedule[task.WBS].Critical || !critical) {
+				continue
+			}
+			for _, from := range froms {
+				if from == "" {
+					from = "Start"
+				}
+				edge := critical
+				if sched, ok := schedule[from]; ok && summaries[d.Task] {
+					edge = critical && sched.Critical
+				}
+				links = append(links, pertLink{From: from, To: task.WBS, Critical: edge, Label: d.Label()})
+			}
+		}
+	}
+	for _, task := range tasks {
+		if !task.IsSummary() && !wbs.InArray(task.WBS, allParents) {
+			sched := schedule[task.WBS]
+			critical := sched.Critical && duration-sched.EF < wbs.CriticalSlack
+			if opts.CriticalOnly && !critical {
+				continue
+			}
+			links = append(links, pertLink{From: task.WBS, To: "Finish", Critical: critical})
+		}
+	}
+	return links
+}
+
+// PertChart writes the PERT network of the tasks in the configured format
+func PertChart(w io.Writer, sheets []wbs.Sheet, opts *Options) error {
+	tasks := pertTasks(sheets, opts)
+	schedule, duration := scheduleTasks(tasks, opts)
+	links := pertLinks(tasks, schedule, duration, opts)
+	tasks = pertNodes(tasks)
+	stdDev := math.Sqrt(float64(wbs.ProjectVariance(schedule, duration)))
+
+	var diagram string
+	switch opts.Format {
+	case MermaidFormat:
+		diagram = pertMermaid(tasks, links, duration, stdDev, opts)
+	case DotFormat:
+		diagram = pertDot(tasks, links, duration, stdDev, opts)
+	case SVGFormat:
+		diagram = pertSVG(tasks, links, duration, stdDev, opts)
+	default:
+		diagram = pertPlantUML(tasks, links, duration, stdDev, opts)
+	}
+	_, err := io.WriteString(w, diagram)
+	return err
+}
+
+// pertPlantUML renders the PERT network as a PlantUML diagram
+func pertPlantUML(tasks []wbs.Sheet, links []pertLink, duration float32, stdDev float64, opts *Options) string {
+	out := bytes.NewBufferString("")
+	out.WriteString("@startuml PERT\n")
+	out.WriteString("left to right direction\n")
+	out.WriteString(fmt.Sprintf(pertStart, 0.0))
+	out.WriteString(fmt.Sprintf(pertFinish, duration))
+	for _, task := range tasks {
+		if opts.CriticalOnly && !task.Schedule.Critical {
+			continue
+		}
+		out.WriteString(task.GetPertNode())
+	}
+	for _, link := range links {
+		out.WriteString(pertEdge(link))
+	}
+	finish := ""
+	if opts.Calendar != nil {
+		finish = fmt.Sprintf(" | Start: %s | Finish: %s", opts.Calendar.StartDate(0).Format(wbs.DateFormat),
+			opts.Calendar.FinishDate(duration).Format(wbs.DateFormat))
+	}
+	out.WriteString(fmt.Sprintf(pertFooter, duration, stdDev, finish))
+	out.WriteString(plantUMLLegend(opts))
+	out.WriteString("@enduml\n")
+	return out.String()
+}
+
+// pertEdge returns the PlantUML arrow between two PERT nodes,
+// drawn bold red when it is part of the critical path and labelled
+// with the dependency type and lag
+func pertEdge(link pertLink) string {
+	arrow := "-->"
+	if link.Critical {
+		arrow = criticalArrow
+	}
+	if link.Label != "" {
+		return fmt.Sprintf("%s %s %s : %s\n", link.From, arrow, link.To, link.Label)
+	}
+	return fmt.Sprintf("%s %s %s\n", link.From, arrow, link.To)
+}
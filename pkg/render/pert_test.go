@@ -0,0 +1,72 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"wbspert/pkg/wbs"
+)
+
+func Test_pertLinksDependencyTypes(t *testing.T) {
+	sheets := []wbs.Sheet{
+		{WBS: "1.1", Title: "Dig", Duration: 4, Status: "Done"},
+		{WBS: "1.2", Title: "Pour", Parents: "1.1SS+2d", Duration: 3, Status: "Waiting"},
+		{WBS: "1.3", Title: "Inspect", Parents: "1.1FF", Duration: 2, Status: "Waiting"},
+	}
+	opts := &Options{Level: 2}
+	tasks := pertTasks(sheets, opts)
+	schedule, duration := wbs.ComputeSchedule(tasks)
+	want := []string{
+		"Start -[#Red,bold]-> 1.1\n",
+		"1.1 -[#Red,bold]-> 1.2 : SS+2d\n",
+		"1.1 --> 1.3 : FF\n",
+		"1.2 -[#Red,bold]-> Finish\n",
+		"1.3 --> Finish\n",
+	}
+	links := pertLinks(tasks, schedule, duration, opts)
+	if len(links) != len(want) {
+		t.Fatalf("pertLinks() = %+v, want %d links", links, len(want))
+	}
+	for i, link := range links {
+		if got := pertEdge(link); got != want[i] {
+			t.Errorf("pertEdge(%+v) = %q, want %q", link, got, want[i])
+		}
+	}
+}
+
+func Test_pertLinksSummary(t *testing.T) {
+	sheets := []wbs.Sheet{
+		{WBS: "1", Title: "Phase", Type: "summary"},
+		{WBS: "1.1", Title: "Design", Duration: 5, Status: "Waiting"},
+		{WBS: "2", Title: "Build", Parents: "1", Duration: 3, Status: "Waiting"},
+	}
+	opts := &Options{Level: 1}
+	tasks := pertTasks(sheets, opts)
+	schedule, duration := wbs.ComputeSchedule(tasks)
+	if got := schedule["2"].ES; got != 5 {
+		t.Errorf("ES of a task after a summary = %v, want 5", got)
+	}
+	want := []string{
+		"Start -[#Red,bold]-> 1.1\n",
+		"1.1 -[#Red,bold]-> 2\n",
+		"2 -[#Red,bold]-> Finish\n",
+	}
+	links := pertLinks(tasks, schedule, duration, opts)
+	if len(links) != len(want) {
+		t.Fatalf("pertLinks() = %+v, want %d links", links, len(want))
+	}
+	for i, link := range links {
+		if got := pertEdge(link); got != want[i] {
+			t.Errorf("pertEdge(%+v) = %q, want %q", link, got, want[i])
+		}
+	}
+
+	buf := bytes.NewBufferString("")
+	if err := PertChart(buf, sheets, opts); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), `"1: Phase"`) {
+		t.Errorf("PertChart() drew the summary task:\n%s", buf.String())
+	}
+}
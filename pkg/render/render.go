@@ -0,0 +1,187 @@
+// Package render writes the charts and tables generated from a
+// work breakdown structure.  Every renderer writes its output to an
+// io.Writer; EmbedFile places that output between the embed tags of
+// an existing document.
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+
+	"wbspert/pkg/wbs"
+)
+
+// Options controls what the renderers include and how they format it
+type Options struct {
+	// Level is the lowest WBS level shown in the PERT chart
+	Level int
+	// ActiveOnly leaves out completed tasks
+	ActiveOnly bool
+	// Filter limits the tables and Kanban to a label value
+	Filter string
+	// Column is the field used for the Kanban columns
+	Column string
+	// CriticalOnly limits the PERT chart to the critical path
+	CriticalOnly bool
+	// Format is the diagram format: plantuml, mermaid, dot or svg
+	Format string
+	// Start is the project start date (YYYY-MM-DD) for the Gantt chart
+	Start string
+	// Trials is the number of Monte Carlo simulation runs
+	Trials int
+	// Distribution used to sample task durations: beta or triangular
+	Distribution string
+	// Seed for the simulation; zero uses the current time
+	Seed int64
+	// EpicDir is the directory epic stories are written to
+	EpicDir string
+	// KeepOrder renders the tasks in input order instead of
+	// sorting them by WBS code
+	KeepOrder bool
+	// LoadPeriod is the period the resource load is reported by:
+	// day or week
+	LoadPeriod string
+	// LevelResources delays tasks so no assignee is double-booked
+	LevelResources bool
+	// Calendar places the schedule on working days.  Without one
+	// the schedule is shown as offsets from the start.
+	Calendar *wbs.Calendar
+	// Statuses is the status vocabulary that colors the tasks and
+	// says which are complete.  When empty the defaults are used.
+	Statuses []wbs.StatusStyle
+}
+
+// Diagram formats
+const (
+	PlantUMLFormat = "plantuml"
+	MermaidFormat  = "mermaid"
+	DotFormat      = "dot"
+	SVGFormat      = "svg"
+)
+
+// Embed tags for each kind of output
+const (
+	WBSTag       = "wbs"
+	WBSTableTag  = "wbsTable"
+	PertTag      = "pert"
+	KanbanTag    = "kanban"
+	BugTag       = "bug"
+	EpicTag      = "epic"
+	SimTag       = "simulation"
+	GanttTag     = "gantt"
+	LoadTag      = "load"
+	LevelingTag  = "leveling"
+	MilestoneTag = "milestones"
+)
+
+const embedPattern = `(?m:^ *)<!--\s*%s:embed:start\s*-->(?s:.*?)<!--\s*%s:embed:end\s*-->(?m:\s*?$)`
+
+// legendEntry is a status color shown in the chart legends
+type legendEntry struct {
+	Color string
+	Label string
+}
+
+// legendEntries returns the labeled status categories of the
+// options' vocabulary
+func legendEntries(opts *Options) []legendEntry {
+	var entries []legendEntry
+	for _, style := range wbs.StatusesOrDefault(opts.Statuses) {
+		if style.Label != "" && style.Color != "" {
+			entries = append(entries, legendEntry{Color: style.Color, Label: style.Label})
+		}
+	}
+	return entries
+}
+
+// plantUMLLegend builds the PlantUML legend block from the legend entries
+func plantUMLLegend(opts *Options) string {
+	out := bytes.NewBufferString("\nlegend right\n")
+	out.WriteString("\t<size:18><u>Legend</u></size>\n")
+	for _, entry := range legendEntries(opts) {
+		out.WriteString(fmt.Sprintf("\t<back:%s>%s</back>\n", entry.Color, entry.Label))
+	}
+	out.WriteString("\t<color:Red><b>Critical path</b></color>\n")
+	out.WriteString("end legend\n")
+	return out.String()
+}
+
+// orderTasks returns a copy of the tasks using the options' status
+// vocabulary and calendar, sorted by WBS code unless the options
+// keep the input order
+func orderTasks(sheets []wbs.Sheet, opts *Options) []wbs.Sheet {
+	tasks := wbs.WithCalendar(wbs.WithStatuses(sheets, opts.Statuses), opts.Calendar)
+	if opts.KeepOrder {
+		return tasks
+	}
+	return wbs.SortSheets(tasks)
+}
+
+// scheduleTasks computes the schedule of the tasks, leveled when the
+// options ask for it, and stores each task's schedule on the task
+func scheduleTasks(tasks []wbs.Sheet, opts *Options) (map[string]*wbs.Schedule, float32) {
+	schedule, duration := wbs.ComputeSchedule(tasks)
+	if opts.LevelResources {
+		schedule, duration = wbs.LevelSchedule(tasks, schedule)
+	}
+	if opts.Calendar != nil {
+		opts.Calendar.SetDates(schedule)
+	}
+	for i := range tasks {
+		if sched, ok := schedule[tasks[i].WBS]; ok {
+			tasks[i].Schedule = *sched
+		}
+	}
+	return schedule, duration
+}
+
+// Fence wraps a diagram in a markdown code block for its format
+func Fence(format, diagram string) string {
+	return fmt.Sprintf("```%s\n%s\n```\n", format, diagram)
+}
+
+// EmbedContents places text between the start and end embed tags
+// in the document.  If the tags aren't found they are added to the
+// end of the document.
+func EmbedContents(doc []byte, text, tag string) []byte {
+	re := regexp.MustCompile(fmt.Sprintf(embedPattern, regexp.QuoteMeta(tag), regexp.QuoteMeta(tag)))
+	embedText := fmt.Sprintf("<!-- %s:embed:start -->\n\n%s\n<!-- %s:embed:end -->\n", tag, text, tag)
+
+	var replacements int
+	doc = re.ReplaceAllFunc(doc, func(_ []byte) []byte {
+		replacements++
+		return []byte(embedText)
+	})
+
+	if replacements == 0 {
+		doc = []byte(fmt.Sprintf("%s\n\n%s", string(doc), embedText))
+	}
+	return doc
+}
+
+// EmbedFile embeds text under the tag in the file at path, creating
+// the file if it doesn't exist
+func EmbedFile(path, text, tag string) error {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+	data = EmbedContents(data, text, tag)
+	if _, err := file.Seek(0, 0); err != nil {
+		return err
+	}
+	if err := file.Truncate(0); err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	return err
+}
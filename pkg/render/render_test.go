@@ -0,0 +1,29 @@
+package render
+
+import "testing"
+
+func TestEmbedContents(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{
+			name: "Replace between tags",
+			doc:  "# Plan\n<!-- pert:embed:start -->\nold\n<!-- pert:embed:end -->\nafter\n",
+			want: "# Plan\n<!-- pert:embed:start -->\n\nnew\n<!-- pert:embed:end -->\n\nafter\n",
+		},
+		{
+			name: "Append when no tags",
+			doc:  "# Plan",
+			want: "# Plan\n\n<!-- pert:embed:start -->\n\nnew\n<!-- pert:embed:end -->\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(EmbedContents([]byte(tt.doc), "new", PertTag)); got != tt.want {
+				t.Errorf("EmbedContents() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
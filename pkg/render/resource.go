@@ -0,0 +1,71 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"wbspert/pkg/wbs"
+)
+
+// LoadReport writes a markdown table of the periods in which an
+// assignee is booked for more than 100% of their time, along with
+// the tasks that conflict.  With a calendar the periods are shown by
+// the date they start.
+func LoadReport(w io.Writer, sheets []wbs.Sheet, opts *Options) error {
+	tasks, schedule := ganttTasks(sheets, opts)
+	over := wbs.Overallocations(wbs.ResourceLoad(tasks, schedule, opts.LoadPeriod, opts.Calendar))
+
+	period := "Day"
+	if opts.LoadPeriod == wbs.WeekPeriod {
+		period = "Week"
+		if opts.Calendar != nil {
+			period = "Week of"
+		}
+	}
+	out := bytes.NewBufferString("")
+	if len(over) == 0 {
+		out.WriteString("No one is over-allocated\n")
+	} else {
+		out.WriteString(fmt.Sprintf("| Assignee | %s | Load | Tasks |\n", period))
+		out.WriteString("| --- | --- | --- | --- |\n")
+		for _, alloc := range over {
+			label := fmt.Sprintf("%d", alloc.Period+1)
+			if opts.Calendar != nil {
+				label = opts.Calendar.PeriodStart(opts.LoadPeriod, alloc.Period).Format(wbs.DateFormat)
+			}
+			out.WriteString(fmt.Sprintf("| %s | %s | %0.0f%% | %s |\n", alloc.Assignee, label, alloc.Load*100, strings.Join(alloc.Tasks, ", ")))
+		}
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// LevelingReport writes a markdown table of the tasks resource
+// leveling moved, with their start and finish before and after
+func LevelingReport(w io.Writer, sheets []wbs.Sheet, opts *Options) error {
+	leveledOpts := *opts
+	leveledOpts.LevelResources = true
+	tasks, _ := ganttTasks(sheets, &leveledOpts)
+
+	out := bytes.NewBufferString("")
+	out.WriteString("| WBS | Task | Assignee | Start | Finish | Leveled start | Leveled finish | Moved |\n")
+	out.WriteString("| --- | --- | --- | --- | --- | --- | --- | --- |\n")
+	moved := 0
+	for _, task := range tasks {
+		sched := task.Schedule
+		if sched.Shift < wbs.CriticalSlack || task.IsSummary() {
+			continue
+		}
+		moved++
+		out.WriteString(fmt.Sprintf("| %s | %s | %s | %0.1f | %0.1f | %0.1f | %0.1f | +%0.1f |\n",
+			task.WBS, task.Title, task.Assignee, sched.ES-sched.Shift, sched.EF-sched.Shift, sched.ES, sched.EF, sched.Shift))
+	}
+	if moved == 0 {
+		out.Reset()
+		out.WriteString("No tasks were moved by resource leveling\n")
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}
@@ -0,0 +1,75 @@
+package render
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"wbspert/pkg/wbs"
+)
+
+func TestResourceReports(t *testing.T) {
+	sheets := []wbs.Sheet{
+		{WBS: "1", Title: "Design", Duration: 2, Assignee: "alice"},
+		{WBS: "2", Title: "Docs", Duration: 1, Assignee: "alice"},
+		{WBS: "3", Title: "Build", Duration: 3, Parents: "1", Assignee: "bob"},
+	}
+	tests := []struct {
+		name   string
+		sheets []wbs.Sheet
+		opts   *Options
+		render func(*bytes.Buffer, []wbs.Sheet, *Options) error
+		want   string
+	}{
+		{
+			name:   "Moved tasks",
+			sheets: sheets,
+			render: func(b *bytes.Buffer, s []wbs.Sheet, o *Options) error { return LevelingReport(b, s, o) },
+			want: "| WBS | Task | Assignee | Start | Finish | Leveled start | Leveled finish | Moved |\n" +
+				"| --- | --- | --- | --- | --- | --- | --- | --- |\n" +
+				"| 2 | Docs | alice | 0.0 | 1.0 | 2.0 | 3.0 | +2.0 |\n",
+		},
+		{
+			name:   "Nothing moved",
+			sheets: sheets[2:],
+			render: func(b *bytes.Buffer, s []wbs.Sheet, o *Options) error { return LevelingReport(b, s, o) },
+			want:   "No tasks were moved by resource leveling\n",
+		},
+		{
+			name:   "Over-allocation",
+			sheets: sheets,
+			render: func(b *bytes.Buffer, s []wbs.Sheet, o *Options) error { return LoadReport(b, s, o) },
+			want: "| Assignee | Day | Load | Tasks |\n" +
+				"| --- | --- | --- | --- |\n" +
+				"| alice | 1 | 200% | 1, 2 |\n",
+		},
+		{
+			name: "Over-allocation by week",
+			sheets: []wbs.Sheet{
+				{WBS: "1", Title: "Design", Duration: 10, Assignee: "alice"},
+				{WBS: "2", Title: "Docs", Duration: 10, Assignee: "alice"},
+			},
+			opts:   &Options{LoadPeriod: wbs.WeekPeriod, Calendar: wbs.NewCalendar(time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC))},
+			render: func(b *bytes.Buffer, s []wbs.Sheet, o *Options) error { return LoadReport(b, s, o) },
+			want: "| Assignee | Week of | Load | Tasks |\n" +
+				"| --- | --- | --- | --- |\n" +
+				"| alice | 2024-03-06 | 200% | 1, 2 |\n" +
+				"| alice | 2024-03-13 | 200% | 1, 2 |\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := bytes.NewBufferString("")
+			opts := tt.opts
+			if opts == nil {
+				opts = &Options{}
+			}
+			if err := tt.render(out, tt.sheets, opts); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", out.String(), tt.want)
+			}
+		})
+	}
+}
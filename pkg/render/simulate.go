@@ -0,0 +1,43 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+
+	"wbspert/pkg/wbs"
+)
+
+// simPercentiles are the completion percentiles reported by
+// the simulation
+var simPercentiles = []int{50, 80, 95}
+
+// Simulate runs opts.Trials Monte Carlo simulations of the PERT
+// network and writes the completion percentiles and the criticality
+// of each task as markdown tables
+func Simulate(w io.Writer, sheets []wbs.Sheet, opts *Options) error {
+	seed := opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	tasks := pertTasks(sheets, opts)
+	sim := wbs.Simulate(tasks, opts.Trials, rand.New(rand.NewSource(seed)), opts.Distribution)
+
+	out := bytes.NewBufferString("")
+	out.WriteString("| Percentile | Duration |\n")
+	out.WriteString("| --- | --- |\n")
+	for _, p := range simPercentiles {
+		out.WriteString(fmt.Sprintf("| P%d | %0.1f |\n", p, sim.Percentile(p)))
+	}
+	out.WriteString("\n")
+	out.WriteString("| WBS | Task | Criticality |\n")
+	out.WriteString("| --- | --- | --- |\n")
+	for _, task := range tasks {
+		out.WriteString(fmt.Sprintf("| %s | %s | %0.1f%% |\n", task.WBS, task.Title, sim.Criticality(task.WBS)*100))
+	}
+	out.WriteString(fmt.Sprintf("\n%d trials, %s distribution, seed %d\n", sim.Trials, opts.Distribution, seed))
+	_, err := io.WriteString(w, out.String())
+	return err
+}
@@ -0,0 +1,303 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+
+	"wbspert/pkg/wbs"
+)
+
+// Sizes used when laying out the SVG charts
+const (
+	svgMargin    = 20
+	svgNodeW     = 200
+	svgNodeH     = 110
+	svgWBSNodeW  = 180
+	svgWBSNodeH  = 40
+	svgGapX      = 70
+	svgGapY      = 30
+	svgWBSGapX   = 20
+	svgLineH     = 16
+	svgLegendRow = 20
+	svgRadius    = 35
+	svgMaxTitle  = 26
+)
+
+const svgDefs = `<defs>
+<marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="6" markerHeight="6" orient="auto"><path d="M 0 0 L 10 5 L 0 10 z" fill="black"/></marker>
+<marker id="arrow-critical" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="6" markerHeight="6" orient="auto"><path d="M 0 0 L 10 5 L 0 10 z" fill="red"/></marker>
+</defs>
+`
+
+// svgText escapes and shortens text for an SVG label
+func svgText(text string, max int) string {
+	if max > 0 && len([]rune(text)) > max {
+		text = string([]rune(text)[:max-1]) + "…"
+	}
+	return html.EscapeString(text)
+}
+
+// svgFill returns the fill color for a task's status
+func svgFill(s *wbs.Sheet) string {
+	if color := s.StatusColor(); color != "" {
+		return color
+	}
+	return "white"
+}
+
+// svgOpen writes the opening svg element and marker definitions
+func svgOpen(out *bytes.Buffer, width, height int) {
+	out.WriteString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n", width, height, width, height))
+	out.WriteString(svgDefs)
+	out.WriteString(fmt.Sprintf(`<rect width="%d" height="%d" fill="white"/>`+"\n", width, height))
+}
+
+// svgLegend writes the status legend starting at the given position
+func svgLegend(out *bytes.Buffer, x, y int, opts *Options) {
+	out.WriteString(fmt.Sprintf(`<text x="%d" y="%d" font-size="16" text-decoration="underline">Legend</text>`+"\n", x, y+14))
+	row := y + svgLegendRow
+	for _, entry := range legendEntries(opts) {
+		out.WriteString(fmt.Sprintf(`<rect x="%d" y="%d" width="14" height="14" fill="%s" stroke="black"/>`+"\n", x, row+3, entry.Color))
+		out.WriteString(fmt.Sprintf(`<text x="%d" y="%d">%s</text>`+"\n", x+20, row+14, svgText(entry.Label, 0)))
+		row += svgLegendRow
+	}
+	out.WriteString(fmt.Sprintf(`<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="red" stroke-width="3"/>`+"\n", x, row+10, x+14, row+10))
+	out.WriteString(fmt.Sprintf(`<text x="%d" y="%d" fill="red" font-weight="bold">Critical path</text>`+"\n", x+20, row+14))
+}
+
+// svgLegendHeight is the space needed by svgLegend
+func svgLegendHeight(opts *Options) int {
+	return (len(legendEntries(opts)) + 2) * svgLegendRow
+}
+
+// svgNode is a positioned node of the PERT network
+type svgNode struct {
+	X, Y int
+	Task *wbs.Sheet
+}
+
+// pertSVG lays out the PERT network in layers, with every node
+// placed one layer to the right of its latest predecessor, and
+// renders it as an SVG image
+func pertSVG(tasks []wbs.Sheet, links []pertLink, duration float32, stdDev float64, opts *Options) string {
+	var ids []string
+	byID := make(map[string]*wbs.Sheet)
+	addID := func(id string, task *wbs.Sheet) {
+		if _, ok := byID[id]; !ok {
+			ids = append(ids, id)
+			byID[id] = task
+		}
+	}
+	addID("Start", nil)
+	for i := range tasks {
+		if opts.CriticalOnly && !tasks[i].Schedule.Critical {
+			continue
+		}
+		addID(tasks[i].WBS, &tasks[i])
+	}
+	for _, link := range links {
+		if link.From != "Finish" {
+			addID(link.From, nil)
+		}
+	}
+
+	rank := make(map[string]int)
+	for range ids {
+		for _, link := range links {
+			if link.To != "Finish" && rank[link.From]+1 > rank[link.To] && rank[link.From] < len(ids) {
+				rank[link.To] = rank[link.From] + 1
+			}
+		}
+	}
+	var maxRank int
+	for _, id := range ids {
+		if rank[id] > maxRank {
+			maxRank = rank[id]
+		}
+	}
+	ids = append(ids, "Finish")
+	rank["Finish"] = maxRank + 1
+
+	nodes := make(map[string]*svgNode)
+	rows := make([]int, maxRank+2)
+	var maxRows int
+	for _, id := range ids {
+		r := rank[id]
+		nodes[id] = &svgNode{
+			X:    svgMargin + r*(svgNodeW+svgGapX),
+			Y:    svgMargin + rows[r]*(svgNodeH+svgGapY),
+			Task: byID[id],
+		}
+		rows[r]++
+		if rows[r] > maxRows {
+			maxRows = rows[r]
+		}
+	}
+
+	width := 2*svgMargin + (maxRank+2)*(svgNodeW+svgGapX) - svgGapX
+	graphH := maxRows*(svgNodeH+svgGapY) - svgGapY
+	height := 2*svgMargin + graphH + svgGapY + 2*svgLineH + svgLegendHeight(opts)
+
+	out := bytes.NewBufferString("")
+	svgOpen(out, width, height)
+	for _, link := range links {
+		from, to := nodes[link.From], nodes[link.To]
+		x1, x2 := from.X+svgNodeW, to.X
+		if from.Task == nil {
+			x1 = from.X + svgNodeW/2 + svgRadius
+		}
+		if to.Task == nil {
+			x2 = to.X + svgNodeW/2 - svgRadius
+		}
+		stroke, marker := `stroke="black" stroke-width="1"`, "arrow"
+		if link.Critical {
+			stroke, marker = `stroke="red" stroke-width="3"`, "arrow-critical"
+		}
+		y1, y2 := from.Y+svgNodeH/2, to.Y+svgNodeH/2
+		out.WriteString(fmt.Sprintf(`<line x1="%d" y1="%d" x2="%d" y2="%d" %s marker-end="url(#%s)"/>`+"\n",
+			x1, y1, x2, y2, stroke, marker))
+		if link.Label != "" {
+			out.WriteString(fmt.Sprintf(`<text x="%d" y="%d" text-anchor="middle">%s</text>`+"\n",
+				(x1+x2)/2, (y1+y2)/2-4, svgText(link.Label, 0)))
+		}
+	}
+	for _, id := range ids {
+		node := nodes[id]
+		if node.Task == nil {
+			var label string
+			if id == "Finish" {
+				label = fmt.Sprintf("%0.1f", duration)
+			} else if id == "Start" {
+				label = "0.0"
+			}
+			cx, cy := node.X+svgNodeW/2, node.Y+svgNodeH/2
+			out.WriteString(fmt.Sprintf(`<circle cx="%d" cy="%d" r="%d" fill="white" stroke="black"/>`+"\n", cx, cy, svgRadius))
+			out.WriteString(fmt.Sprintf(`<text x="%d" y="%d" text-anchor="middle">%s</text>`+"\n", cx, cy-2, svgText(id, 0)))
+			out.WriteString(fmt.Sprintf(`<text x="%d" y="%d" text-anchor="middle">%s</text>`+"\n", cx, cy+svgLineH-2, svgText(label, 0)))
+			continue
+		}
+		task, sched := node.Task, node.Task.Schedule
+		stroke := `stroke="black" stroke-width="1"`
+		if sched.Critical {
+			stroke = `stroke="red" stroke-width="3"`
+		}
+		if task.IsMilestone() {
+			cx, cy := node.X+svgNodeW/2, node.Y+svgNodeH/2
+			out.WriteString(fmt.Sprintf(`<polygon points="%d,%d %d,%d %d,%d %d,%d" fill="%s" %s/>`+"\n",
+				cx, node.Y, node.X+svgNodeW, cy, cx, node.Y+svgNodeH, node.X, cy, svgFill(task), stroke))
+			lines := []string{fmt.Sprintf("%s: %s", task.WBS, task.Title), milestoneDay(task)}
+			for i, line := range lines {
+				out.WriteString(fmt.Sprintf(`<text x="%d" y="%d" text-anchor="middle">%s</text>`+"\n", cx, cy+svgLineH*i, svgText(line, svgMaxTitle/2)))
+			}
+			continue
+		}
+		out.WriteString(fmt.Sprintf(`<rect x="%d" y="%d" width="%d" height="%d" rx="4" fill="%s" %s/>`+"\n",
+			node.X, node.Y, svgNodeW, svgNodeH, svgFill(task), stroke))
+		lines := []string{
+			fmt.Sprintf("%s: %s", task.WBS, task.Title),
+			fmt.Sprintf("Status: %s", task.Status),
+			fmt.Sprintf("ES: %0.1f | EF: %0.1f", sched.ES, sched.EF),
+			fmt.Sprintf("Duration: %0.1f", task.Expected()),
+			fmt.Sprintf("LS: %0.1f | LF: %0.1f", sched.LS, sched.LF),
+			fmt.Sprintf("Slack: %0.1f", sched.Slack),
+		}
+		for i, line := range lines {
+			weight := ""
+			if i == 0 {
+				weight = ` font-weight="bold"`
+			}
+			out.WriteString(fmt.Sprintf(`<text x="%d" y="%d"%s>%s</text>`+"\n", node.X+6, node.Y+svgLineH*(i+1), weight, svgText(line, svgMaxTitle+4)))
+		}
+	}
+	footer := svgMargin + graphH + svgGapY
+	out.WriteString(fmt.Sprintf(`<text x="%d" y="%d">Expected duration: %0.1f | Std dev: %0.2f</text>`+"\n", svgMargin, footer+svgLineH, duration, stdDev))
+	svgLegend(out, svgMargin, footer+2*svgLineH, opts)
+	out.WriteString("</svg>\n")
+	return out.String()
+}
+
+// wbsTreeNode is a node of the work breakdown tree used for the
+// SVG layout
+type wbsTreeNode struct {
+	Task     *wbs.Sheet
+	Level    int
+	Children []*wbsTreeNode
+	X, Y     int
+}
+
+// wbsSVG renders the work breakdown structure as a top down tree.
+// Leaves are placed side by side and every parent is centered over
+// its children.
+func wbsSVG(sheets []wbs.Sheet, opts *Options) string {
+	root := &wbsTreeNode{}
+	stack := []*wbsTreeNode{root}
+	for i := range sheets {
+		if opts.ActiveOnly && sheets[i].IsCompleted() {
+			continue
+		}
+		lvl := sheets[i].GetLevel()
+		for len(stack) > 1 && stack[len(stack)-1].Level >= lvl {
+			stack = stack[:len(stack)-1]
+		}
+		node := &wbsTreeNode{Task: &sheets[i], Level: lvl}
+		parent := stack[len(stack)-1]
+		parent.Children = append(parent.Children, node)
+		stack = append(stack, node)
+	}
+
+	var leaves, depth int
+	var place func(node *wbsTreeNode, d int)
+	place = func(node *wbsTreeNode, d int) {
+		node.Y = svgMargin + d*(svgWBSNodeH+svgGapY)
+		if d > depth {
+			depth = d
+		}
+		if len(node.Children) == 0 {
+			node.X = svgMargin + leaves*(svgWBSNodeW+svgWBSGapX)
+			leaves++
+			return
+		}
+		for _, child := range node.Children {
+			place(child, d+1)
+		}
+		first, last := node.Children[0], node.Children[len(node.Children)-1]
+		node.X = (first.X + last.X) / 2
+	}
+	place(root, 0)
+
+	width := 2*svgMargin + leaves*(svgWBSNodeW+svgWBSGapX) - svgWBSGapX
+	if min := 2*svgMargin + svgWBSNodeW; width < min {
+		width = min
+	}
+	graphH := (depth+1)*(svgWBSNodeH+svgGapY) - svgGapY
+	height := 2*svgMargin + graphH + svgGapY + svgLineH + svgLegendHeight(opts)
+
+	out := bytes.NewBufferString("")
+	svgOpen(out, width, height)
+	var draw func(node *wbsTreeNode)
+	draw = func(node *wbsTreeNode) {
+		for _, child := range node.Children {
+			midY := node.Y + svgWBSNodeH + svgGapY/2
+			out.WriteString(fmt.Sprintf(`<polyline points="%d,%d %d,%d %d,%d %d,%d" fill="none" stroke="black"/>`+"\n",
+				node.X+svgWBSNodeW/2, node.Y+svgWBSNodeH, node.X+svgWBSNodeW/2, midY,
+				child.X+svgWBSNodeW/2, midY, child.X+svgWBSNodeW/2, child.Y))
+		}
+		label, fill := "Project", "white"
+		if node.Task != nil {
+			label = node.Task.Label()
+			fill = svgFill(node.Task)
+		}
+		out.WriteString(fmt.Sprintf(`<rect x="%d" y="%d" width="%d" height="%d" rx="4" fill="%s" stroke="black"/>`+"\n",
+			node.X, node.Y, svgWBSNodeW, svgWBSNodeH, fill))
+		out.WriteString(fmt.Sprintf(`<text x="%d" y="%d" text-anchor="middle">%s</text>`+"\n",
+			node.X+svgWBSNodeW/2, node.Y+svgWBSNodeH/2+4, svgText(label, svgMaxTitle)))
+		for _, child := range node.Children {
+			draw(child)
+		}
+	}
+	draw(root)
+	svgLegend(out, svgMargin, svgMargin+graphH+svgGapY, opts)
+	out.WriteString("</svg>\n")
+	return out.String()
+}
@@ -0,0 +1,100 @@
+package render
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"wbspert/pkg/wbs"
+)
+
+// checkSVG verifies the image is well formed XML and contains
+// every expected fragment
+func checkSVG(t *testing.T, svg string, want []string) {
+	t.Helper()
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("invalid svg: %s", err)
+		}
+	}
+	for _, w := range want {
+		if !strings.Contains(svg, w) {
+			t.Errorf("svg missing %q", w)
+		}
+	}
+}
+
+func Test_pertSVG(t *testing.T) {
+	sheets := []wbs.Sheet{
+		{WBS: "1.1", Title: "Design & plan", Duration: 2, Status: "Done"},
+		{WBS: "1.2", Title: "Build", Parents: "1.1", Duration: 3, Status: "In Progress"},
+		{WBS: "1.3", Title: "Docs", Parents: "1.1", Duration: 1, Status: "Waiting"},
+	}
+	opts := &Options{Level: 2, Format: SVGFormat}
+	tasks := pertTasks(sheets, opts)
+	schedule, duration := wbs.ComputeSchedule(tasks)
+	for i := range tasks {
+		tasks[i].Schedule = *schedule[tasks[i].WBS]
+	}
+	links := pertLinks(tasks, schedule, duration, opts)
+	checkSVG(t, pertSVG(tasks, links, duration, 0, opts), []string{
+		`<svg xmlns="http://www.w3.org/2000/svg" width="1050"`,
+		`fill="Thistle" stroke="red" stroke-width="3"/>`,
+		`fill="Pink" stroke="black" stroke-width="1"/>`,
+		`1.1: Design &amp; plan`,
+		`<line x1="155" y1="75" x2="290" y2="75" stroke="red" stroke-width="3" marker-end="url(#arrow-critical)"/>`,
+		`<text x="566" y="176" font-weight="bold">1.3: Docs</text>`,
+		`Expected duration: 5.0`,
+		`>Critical path</text>`,
+	})
+}
+
+func Test_wbsSVG(t *testing.T) {
+	sheets := []wbs.Sheet{
+		{WBS: "1", Title: "Phase 1"},
+		{WBS: "1.1", Title: "Design", Status: "Done"},
+		{WBS: "1.1.1", Title: "Sketch"},
+		{WBS: "1.2", Title: "Build"},
+	}
+	checkSVG(t, wbsSVG(sheets, &Options{}), []string{
+		`>Project</text>`,
+		`>1.1.1: Sketch</text>`,
+		`fill="Thistle" stroke="black"/>`,
+		`>Legend</text>`,
+	})
+}
+
+func Test_wbsSVGTree(t *testing.T) {
+	sheets := []wbs.Sheet{
+		{WBS: "1", Title: "Phase 1"},
+		{WBS: "1.1", Title: "Design"},
+		{WBS: "2", Title: "Phase 2"},
+	}
+	svg := wbsSVG(sheets, &Options{})
+	nodes := make(map[string][2]int)
+	for _, m := range regexp.MustCompile(`<rect x="(\d+)" y="(\d+)" width="\d+" height="\d+" rx="4"[^>]*/>\n<text[^>]*>([^<]*)</text>`).FindAllStringSubmatch(svg, -1) {
+		x, _ := strconv.Atoi(m[1])
+		y, _ := strconv.Atoi(m[2])
+		nodes[m[3]] = [2]int{x, y}
+	}
+	parent, child := nodes["1: Phase 1"], nodes["1.1: Design"]
+	edge := fmt.Sprintf(`points="%d,%d %d,`, parent[0]+svgWBSNodeW/2, parent[1]+svgWBSNodeH, parent[0]+svgWBSNodeW/2)
+	end := fmt.Sprintf(` %d,%d" fill="none"`, child[0]+svgWBSNodeW/2, child[1])
+	found := false
+	for _, line := range strings.Split(svg, "\n") {
+		found = found || strings.Contains(line, edge) && strings.Contains(line, end)
+	}
+	if !found {
+		t.Errorf("wbsSVG() has no edge from 1 to 1.1:\n%s", svg)
+	}
+	if child[1] <= parent[1] || nodes["2: Phase 2"][1] != parent[1] {
+		t.Errorf("wbsSVG() levels = %v, want 1.1 below 1 and 2 beside it", nodes)
+	}
+}
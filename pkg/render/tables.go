@@ -0,0 +1,109 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"wbspert/pkg/wbs"
+)
+
+// WBSTable writes the tasks as a markdown table.  With a calendar
+// the scheduled dates of each task are added.
+func WBSTable(w io.Writer, sheets []wbs.Sheet, opts *Options) error {
+	out := bytes.NewBufferString("")
+	tasks := orderTasks(sheets, opts)
+	if opts.Calendar != nil {
+		tasks = append([]wbs.Sheet{}, tasks...)
+		scheduleTasks(tasks, opts)
+		out.WriteString(wbs.MarkdownDateHeader())
+	} else {
+		out.WriteString(wbs.MarkdownHeader())
+	}
+	out.WriteString("\n")
+	for _, sheet := range tasks {
+		if opts.ActiveOnly && sheet.IsCompleted() {
+			continue
+		}
+		if len(opts.Filter) > 0 {
+			if !(wbs.InArray(opts.Filter, sheet.Labels) || sheet.HasType(opts.Filter)) {
+				continue
+			}
+		}
+		if opts.Calendar != nil {
+			out.WriteString(sheet.MarkdownDateRow())
+		} else {
+			out.WriteString(sheet.MarkdownRow())
+		}
+		out.WriteString("\n")
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// BugList writes a markdown table of the tasks labeled as bugs
+func BugList(w io.Writer, sheets []wbs.Sheet, opts *Options) error {
+	out := bytes.NewBufferString("")
+	out.WriteString("| Repo | Status | Title |\n")
+	out.WriteString("| --- | --- | --- |\n")
+	for _, sheet := range orderTasks(sheets, opts) {
+		if opts.ActiveOnly && sheet.IsCompleted() {
+			continue
+		}
+		if wbs.InArray("bug", sheet.Labels) {
+			out.WriteString(fmt.Sprintf("| %s | %s | %s |\n", sheet.Repo, sheet.Status, sheet.Title))
+		}
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// EpicList writes a markdown checklist of the epics
+func EpicList(w io.Writer, sheets []wbs.Sheet, opts *Options) error {
+	out := bytes.NewBufferString("")
+
+	for _, sheet := range orderTasks(sheets, opts) {
+		if wbs.InArray("epic", sheet.Labels) || sheet.HasType("epic") {
+			complete := " "
+			if sheet.IsCompleted() {
+				complete = "x"
+			}
+			out.WriteString(fmt.Sprintf("- [%s] %s\n", complete, sheet.Title))
+		}
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+var epicHeader = `---
+title: "%s: %s"
+linkTitle: %s
+---
+
+`
+
+// EpicStories writes a markdown page for every epic to opts.EpicDir
+func EpicStories(sheets []wbs.Sheet, opts *Options) error {
+	if _, err := os.Stat(opts.EpicDir); os.IsNotExist(err) {
+		return fmt.Errorf("epic directory doesn't exist: %s", opts.EpicDir)
+	}
+	for _, sheet := range sheets {
+		if wbs.InArray("epic", sheet.Labels) || sheet.HasType("epic") {
+			out, err := os.Create(path.Join(opts.EpicDir, fmt.Sprintf("%s.md", sheet.WBS)))
+			if err != nil {
+				return fmt.Errorf("error opening file: %w", err)
+			}
+			out.WriteString(fmt.Sprintf(epicHeader, sheet.WBS, sheet.Title, sheet.WBS))
+			out.WriteString(fmt.Sprintf("**Status:** %s \n", sheet.Status))
+			//out.WriteString(fmt.Sprintf("| *Repository* | %s |\n\n", sheet.Repo))
+			out.WriteString("\n")
+			out.WriteString(sheet.Body)
+			if err := out.Close(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
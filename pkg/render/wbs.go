@@ -0,0 +1,44 @@
+package render
+
+import (
+	"bytes"
+	"io"
+
+	"wbspert/pkg/wbs"
+)
+
+// WBS writes the work breakdown structure in the configured format.
+// Placeholders are added for WBS levels that have no task.
+func WBS(w io.Writer, sheets []wbs.Sheet, opts *Options) error {
+	sheets = wbs.FillLevels(orderTasks(sheets, opts))
+	var diagram string
+	switch opts.Format {
+	case MermaidFormat:
+		diagram = wbsMermaid(sheets, opts)
+	case SVGFormat:
+		diagram = wbsSVG(sheets, opts)
+	default:
+		diagram = wbsPlantUML(sheets, opts)
+	}
+	_, err := io.WriteString(w, diagram)
+	return err
+}
+
+// wbsPlantUML renders the work breakdown structure as a PlantUML diagram
+func wbsPlantUML(sheets []wbs.Sheet, opts *Options) string {
+	out := bytes.NewBufferString("")
+
+	out.WriteString("@startwbs\n")
+	out.WriteString("* Project\n")
+	for _, sheet := range sheets {
+		if opts.ActiveOnly && sheet.IsCompleted() {
+			continue
+		}
+		out.WriteString(sheet.GetWBSLevel(99))
+		out.WriteString("\n")
+	}
+	out.WriteString("\nfooter\nAs of %date()\nend footer\n")
+	out.WriteString(plantUMLLegend(opts))
+	out.WriteString("@endwbs\n")
+	return out.String()
+}
@@ -0,0 +1,70 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"wbspert/pkg/wbs"
+)
+
+func TestWBS_order(t *testing.T) {
+	sheets := []wbs.Sheet{
+		{WBS: "1.10", Title: "Ten"},
+		{WBS: "1", Title: "One"},
+		{WBS: "1.2.1", Title: "Deep"},
+		{WBS: "1.2", Title: "Two"},
+		{WBS: "2.1", Title: "Orphan"},
+	}
+	tests := []struct {
+		name      string
+		keepOrder bool
+		want      []string
+	}{
+		{"Sorted", false, []string{"** 1: One", "*** 1.2: Two", "**** 1.2.1: Deep", "*** 1.10: Ten", "** 2", "*** 2.1: Orphan"}},
+		{"Input order", true, []string{"*** 1.10: Ten", "** 1: One", "**** 1.2.1: Deep", "*** 1.2: Two", "** 2", "*** 2.1: Orphan"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := bytes.NewBufferString("")
+			if err := WBS(out, sheets, &Options{KeepOrder: tt.keepOrder}); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, line := range strings.Split(out.String(), "\n") {
+				if strings.HasPrefix(line, "**") {
+					got = append(got, line)
+				}
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("WBS() lines = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWBS_statuses(t *testing.T) {
+	sheets := []wbs.Sheet{
+		{WBS: "1", Title: "Design", Status: "Shipped"},
+		{WBS: "2", Title: "Build", Status: "Done"},
+	}
+	opts := &Options{
+		ActiveOnly: true,
+		Statuses:   []wbs.StatusStyle{{Category: "complete", Statuses: []string{"shipped"}, Color: "Silver", Complete: true, Label: "Shipped"}},
+	}
+	out := bytes.NewBufferString("")
+	if err := WBS(out, sheets, opts); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	for _, want := range []string{"2: Build", "<back:Silver>Shipped</back>"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("WBS() = %s, want %q", got, want)
+		}
+	}
+	for _, unwanted := range []string{"1: Design", "Thistle"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("WBS() = %s, don't want %q", got, unwanted)
+		}
+	}
+}
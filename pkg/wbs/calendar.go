@@ -0,0 +1,331 @@
+package wbs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Duration units
+const (
+	HourUnit = "hours"
+	DayUnit  = "days"
+	WeekUnit = "weeks"
+)
+
+// DateFormat is the layout of the dates in calendars and charts
+const DateFormat = "2006-01-02"
+
+// Calendar places a schedule on real dates.  Durations are counted
+// in Unit and only working days are scheduled.
+type Calendar struct {
+	Start time.Time
+	Unit  string
+	// Weekdays are the days of the week that are worked
+	Weekdays []time.Weekday
+	// Holidays are the days off, keyed by date
+	Holidays    map[string]bool
+	HoursPerDay float32
+}
+
+// ScheduleDates are the calendar dates of a task's schedule.  The
+// finish dates are the last day worked.
+type ScheduleDates struct {
+	ES time.Time
+	EF time.Time
+	LS time.Time
+	LF time.Time
+}
+
+// calendarFile is the layout of a calendar file
+type calendarFile struct {
+	Start       string   `yaml:"start"`
+	Unit        string   `yaml:"unit"`
+	Weekdays    []string `yaml:"weekdays"`
+	HoursPerDay float32  `yaml:"hoursPerDay"`
+	Holidays    []string `yaml:"holidays"`
+	// HolidayFile is an iCalendar file of days off, relative to
+	// the calendar file
+	HolidayFile string `yaml:"holidayFile"`
+}
+
+// NewCalendar returns a calendar starting on the given date with
+// durations in days, Monday to Friday working weeks, eight hour days
+// and no holidays
+func NewCalendar(start time.Time) *Calendar {
+	return &Calendar{
+		Start:       start,
+		Unit:        DayUnit,
+		Weekdays:    []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		Holidays:    make(map[string]bool),
+		HoursPerDay: 8,
+	}
+}
+
+// ReadCalendar loads the calendar file at path.  Settings the file
+// leaves out keep the NewCalendar defaults; when it has no start the
+// given start is used.
+func ReadCalendar(path string, start time.Time) (*Calendar, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file calendarFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	cal := NewCalendar(start)
+	if file.Start != "" {
+		if cal.Start, err = time.Parse(DateFormat, file.Start); err != nil {
+			return nil, fmt.Errorf("%s: invalid start date %s", path, file.Start)
+		}
+	}
+	if cal.Start.IsZero() {
+		return nil, fmt.Errorf("%s: the calendar needs a start date", path)
+	}
+	switch file.Unit {
+	case "":
+	case HourUnit, DayUnit, WeekUnit:
+		cal.Unit = file.Unit
+	default:
+		return nil, fmt.Errorf("%s: unknown unit %s", path, file.Unit)
+	}
+	if file.HoursPerDay < 0 {
+		return nil, fmt.Errorf("%s: hoursPerDay must be positive", path)
+	} else if file.HoursPerDay > 0 {
+		cal.HoursPerDay = file.HoursPerDay
+	}
+	if len(file.Weekdays) > 0 {
+		cal.Weekdays = nil
+		for _, name := range file.Weekdays {
+			day, err := parseWeekday(name)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			cal.Weekdays = append(cal.Weekdays, day)
+		}
+	}
+	for _, holiday := range file.Holidays {
+		day, err := time.Parse(DateFormat, holiday)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid holiday %s", path, holiday)
+		}
+		cal.Holidays[day.Format(DateFormat)] = true
+	}
+	if file.HolidayFile != "" {
+		icsPath := file.HolidayFile
+		if !filepath.IsAbs(icsPath) {
+			icsPath = filepath.Join(filepath.Dir(path), icsPath)
+		}
+		in, err := os.Open(icsPath)
+		if err != nil {
+			return nil, err
+		}
+		defer in.Close()
+		days, err := ReadICalendar(in)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", icsPath, err)
+		}
+		for _, day := range days {
+			cal.Holidays[day.Format(DateFormat)] = true
+		}
+	}
+	return cal, nil
+}
+
+// parseWeekday reads an English day name or its abbreviation
+func parseWeekday(name string) (time.Weekday, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if len(name) >= 3 && strings.HasPrefix(full, name) {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown weekday %s", name)
+}
+
+// ReadICalendar returns every day covered by the events of an
+// iCalendar file.  Recurrence rules aren't expanded.
+func ReadICalendar(in io.Reader) ([]time.Time, error) {
+	var lines []string
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var days []time.Time
+	var start, end time.Time
+	for _, line := range lines {
+		name, value := line, ""
+		if i := strings.Index(line, ":"); i >= 0 {
+			name, value = line[:i], line[i+1:]
+		}
+		if i := strings.Index(name, ";"); i >= 0 {
+			name = name[:i]
+		}
+		var err error
+		switch strings.ToUpper(name) {
+		case "BEGIN":
+			if strings.ToUpper(value) == "VEVENT" {
+				start, end = time.Time{}, time.Time{}
+			}
+		case "DTSTART":
+			start, err = parseICalDate(value)
+		case "DTEND":
+			end, err = parseICalDate(value)
+		case "END":
+			if strings.ToUpper(value) != "VEVENT" || start.IsZero() {
+				continue
+			}
+			days = append(days, start)
+			for day := start.AddDate(0, 0, 1); day.Before(end); day = day.AddDate(0, 0, 1) {
+				days = append(days, day)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return days, nil
+}
+
+// parseICalDate reads the date of an iCalendar DATE or DATE-TIME value
+func parseICalDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid date %s", value)
+	}
+	day, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %s", value)
+	}
+	return day, nil
+}
+
+// IsWorkingDay returns true if the day is a working weekday and
+// not a holiday
+func (c *Calendar) IsWorkingDay(day time.Time) bool {
+	if c.Holidays[day.Format(DateFormat)] {
+		return false
+	}
+	for _, weekday := range c.Weekdays {
+		if day.Weekday() == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// Days converts a duration in the calendar's unit to working days
+func (c *Calendar) Days(duration float32) float64 {
+	switch c.Unit {
+	case HourUnit:
+		return float64(duration / c.HoursPerDay)
+	case WeekUnit:
+		return float64(duration) * c.weekLength()
+	}
+	return float64(duration)
+}
+
+// Units converts working days to a duration in the calendar's unit
+func (c *Calendar) Units(days float64) float32 {
+	switch c.Unit {
+	case HourUnit:
+		return float32(days) * c.HoursPerDay
+	case WeekUnit:
+		return float32(days / c.weekLength())
+	}
+	return float32(days)
+}
+
+// weekLength returns the number of working days in a week; a
+// calendar without weekdays works every day
+func (c *Calendar) weekLength() float64 {
+	if len(c.Weekdays) == 0 {
+		return 7
+	}
+	return float64(len(c.Weekdays))
+}
+
+// WithCalendar returns a copy of the tasks whose dependency lags are
+// converted through the calendar when they are scheduled
+func WithCalendar(sheets []Sheet, cal *Calendar) []Sheet {
+	tasks := append([]Sheet{}, sheets...)
+	for i := range tasks {
+		tasks[i].calendar = cal
+	}
+	return tasks
+}
+
+// workingDay returns the nth working day of the project, counting
+// the first working day on or after the start as zero
+func (c *Calendar) workingDay(n int) time.Time {
+	day := c.Start
+	if len(c.Weekdays) == 0 {
+		return day.AddDate(0, 0, n)
+	}
+	for !c.IsWorkingDay(day) {
+		day = day.AddDate(0, 0, 1)
+	}
+	for ; n > 0; n-- {
+		day = day.AddDate(0, 0, 1)
+		for !c.IsWorkingDay(day) {
+			day = day.AddDate(0, 0, 1)
+		}
+	}
+	return day
+}
+
+// StartDate returns the day work begins at the given schedule offset
+func (c *Calendar) StartDate(offset float32) time.Time {
+	return c.workingDay(int(math.Floor(c.Days(offset) + CriticalSlack)))
+}
+
+// FinishDate returns the last day worked by a task finishing at the
+// given schedule offset
+func (c *Calendar) FinishDate(offset float32) time.Time {
+	days := int(math.Ceil(c.Days(offset)-CriticalSlack)) - 1
+	if days < 0 {
+		days = 0
+	}
+	return c.workingDay(days)
+}
+
+// SetDates fills in the calendar dates of every task in the schedule
+func (c *Calendar) SetDates(schedule map[string]*Schedule) {
+	for _, node := range schedule {
+		dates := &ScheduleDates{
+			ES: c.StartDate(node.ES),
+			EF: c.FinishDate(node.EF),
+			LS: c.StartDate(node.LS),
+			LF: c.FinishDate(node.LF),
+		}
+		// A milestone finishes on the day it starts, not the day before
+		if dates.EF.Before(dates.ES) {
+			dates.EF = dates.ES
+		}
+		if dates.LF.Before(dates.LS) {
+			dates.LF = dates.LS
+		}
+		node.Dates = dates
+	}
+}
@@ -0,0 +1,139 @@
+package wbs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const holidayICS = "BEGIN:VCALENDAR\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20240329\r\n" +
+	"DTEND;VALUE=DATE:20240402\r\n" +
+	"SUMMARY:Easter\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+const alarmICS = "BEGIN:VCALENDAR\r\n" +
+	"BEGIN:VTIMEZONE\r\n" +
+	"TZID:Europe/London\r\n" +
+	"BEGIN:STANDARD\r\n" +
+	"DTSTART:19701025T020000\r\n" +
+	"END:STANDARD\r\n" +
+	"END:VTIMEZONE\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20240506\r\n" +
+	"DTEND;VALUE=DATE:20240507\r\n" +
+	"SUMMARY:Bank holiday\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"TRIGGER:-PT15M\r\n" +
+	"ACTION:DISPLAY\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func Test_ReadICalendar(t *testing.T) {
+	tests := []struct {
+		name string
+		ics  string
+		want string
+	}{
+		{"Event", holidayICS, "2024-03-29 2024-03-30 2024-03-31 2024-04-01"},
+		{"Alarm", alarmICS, "2024-05-06"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days, err := ReadICalendar(strings.NewReader(tt.ics))
+			if err != nil {
+				t.Fatalf("ReadICalendar() error = %v", err)
+			}
+			var got []string
+			for _, day := range days {
+				got = append(got, day.Format(DateFormat))
+			}
+			if strings.Join(got, " ") != tt.want {
+				t.Errorf("ReadICalendar() = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+type calendarDate struct {
+	offset float32
+	date   string
+}
+
+func Test_ReadCalendar(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "holidays.ics"), []byte(holidayICS), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		yaml    string
+		dates   []calendarDate
+		wantErr bool
+	}{
+		{"default weekdays", "start: 2024-03-27\n", []calendarDate{{0, "2024-03-27"}, {3, "2024-04-01"}}, false},
+		{"holidays", "start: 2024-03-27\nholidays: [2024-03-28]\nholidayFile: holidays.ics\n",
+			[]calendarDate{{0, "2024-03-27"}, {1, "2024-04-02"}, {2, "2024-04-03"}}, false},
+		{"six day weeks", "start: 2024-03-29\nweekdays: [mon, tue, wed, thu, fri, saturday]\n",
+			[]calendarDate{{1, "2024-03-30"}, {2, "2024-04-01"}}, false},
+		{"hours", "start: 2024-03-25\nunit: hours\nhoursPerDay: 4\n", []calendarDate{{8, "2024-03-27"}}, false},
+		{"weeks", "start: 2024-03-25\nunit: weeks\n", []calendarDate{{1, "2024-04-01"}}, false},
+		{"no start", "unit: days\n", nil, true},
+		{"bad unit", "start: 2024-03-25\nunit: months\n", nil, true},
+		{"bad weekday", "start: 2024-03-25\nweekdays: [someday]\n", nil, true},
+		{"unknown key", "start: 2024-03-25\nholiday: [2024-03-28]\n", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "calendar.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0644); err != nil {
+				t.Fatal(err)
+			}
+			cal, err := ReadCalendar(path, time.Time{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadCalendar() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, d := range tt.dates {
+				if got := cal.StartDate(d.offset).Format(DateFormat); got != d.date {
+					t.Errorf("StartDate(%v) = %s, want %s", d.offset, got, d.date)
+				}
+			}
+		})
+	}
+}
+
+func Test_CalendarSetDates(t *testing.T) {
+	cal := NewCalendar(time.Date(2024, 3, 28, 0, 0, 0, 0, time.UTC))
+	cal.Holidays["2024-03-29"] = true
+	sheets := []Sheet{
+		{WBS: "1", Duration: 2},
+		{WBS: "2", Duration: 1.5, Parents: "1"},
+		{WBS: "3", Duration: 1},
+		{WBS: "4", Duration: 0, Parents: "1"},
+	}
+	schedule, _ := ComputeSchedule(sheets)
+	cal.SetDates(schedule)
+
+	tests := []struct {
+		wbs            string
+		es, ef, ls, lf string
+	}{
+		{"1", "2024-03-28", "2024-04-01", "2024-03-28", "2024-04-01"},
+		{"2", "2024-04-02", "2024-04-03", "2024-04-02", "2024-04-03"},
+		{"3", "2024-03-28", "2024-03-28", "2024-04-02", "2024-04-03"},
+		{"4", "2024-04-02", "2024-04-02", "2024-04-03", "2024-04-03"},
+	}
+	for _, tt := range tests {
+		d := schedule[tt.wbs].Dates
+		got := []string{d.ES.Format(DateFormat), d.EF.Format(DateFormat), d.LS.Format(DateFormat), d.LF.Format(DateFormat)}
+		want := []string{tt.es, tt.ef, tt.ls, tt.lf}
+		if strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Errorf("SetDates()[%s] = %v, want %v", tt.wbs, got, want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package wbs
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dependency types
+const (
+	FinishToStart  = "FS"
+	StartToStart   = "SS"
+	FinishToFinish = "FF"
+	StartToFinish  = "SF"
+)
+
+// rollUpType links a summary task to a task under it.  The summary
+// spans the tasks under it, so each must finish by the time it does.
+const rollUpType = "rollup"
+
+// Lag units
+const (
+	HourLag = "h"
+	DayLag  = "d"
+	WeekLag = "w"
+)
+
+// dependencyPattern splits a parent such as 1.1SS+2d or design SS
+// into the task, the dependency type, the lag and its unit.  The type must
+// follow a digit, as WBS codes end in one, or a space, so IDs such
+// as CSS or UI-FF are read as they are.  The lag can only follow a
+// type so task IDs containing a hyphen are read as they are.
+var dependencyPattern = regexp.MustCompile(`^(?:(.*[0-9])|(.+?) +)(FS|SS|FF|SF)(?:([+-][0-9]*\.?[0-9]+)([dhw])?)?$`)
+
+// lagUnitPattern matches a lag written with a unit, so a parent that
+// isn't a task can be reported as a lag with an unknown unit
+var lagUnitPattern = regexp.MustCompile(`(FS|SS|FF|SF)[+-][0-9]*\.?[0-9]+[a-zA-Z]+$`)
+
+// Dependency is a link from a task to one of its parents
+type Dependency struct {
+	// Task is the WBS code or ID of the parent
+	Task string
+	// Type says which end of the parent constrains which end of the
+	// task, e.g. SS for start-to-start
+	Type string
+	// Lag is the delay after the parent's end, in Unit or, without
+	// one, in the same unit as the durations.  A negative lag is a
+	// lead.
+	Lag float32
+	// Unit is the lag's suffix: h, d or w
+	Unit string
+}
+
+// ParseDependency reads a parent written as the task followed by an
+// optional type and lag, e.g. 1.1, 1.2FF, 1.1SS+2d or design SS.  A
+// parent without a type is finish-to-start.
+func ParseDependency(parent string) Dependency {
+	parent = strings.TrimSpace(parent)
+	match := dependencyPattern.FindStringSubmatch(parent)
+	if match == nil {
+		return Dependency{Task: parent, Type: FinishToStart}
+	}
+	dep := Dependency{Task: match[1] + match[2], Type: match[3], Unit: match[5]}
+	if match[4] != "" {
+		lag, err := strconv.ParseFloat(match[4], 32)
+		if err != nil {
+			return Dependency{Task: parent, Type: FinishToStart}
+		}
+		dep.Lag = float32(lag)
+	}
+	return dep
+}
+
+// GetDependencies splits the parents and returns them as typed
+// dependencies, with their lags as written
+func (s *Sheet) GetDependencies() []Dependency {
+	var deps []Dependency
+	for _, p := range strings.Split(s.Parents, ",") {
+		deps = append(deps, ParseDependency(p))
+	}
+	return deps
+}
+
+// scheduleDependencies returns the dependencies with their lags in
+// the unit of the task's calendar
+func (s *Sheet) scheduleDependencies() []Dependency {
+	deps := s.GetDependencies()
+	for i := range deps {
+		deps[i] = deps[i].InUnits(s.calendar)
+	}
+	return deps
+}
+
+// InUnits returns the dependency with its lag converted to the unit
+// of the calendar's durations.  Without a calendar durations are in
+// days, with the NewCalendar working week and hours per day.
+func (d Dependency) InUnits(cal *Calendar) Dependency {
+	if d.Unit == "" {
+		return d
+	}
+	if cal == nil {
+		cal = NewCalendar(time.Time{})
+	}
+	days := float64(d.Lag)
+	switch d.Unit {
+	case HourLag:
+		days = float64(d.Lag / cal.HoursPerDay)
+	case WeekLag:
+		days = float64(d.Lag) * cal.weekLength()
+	}
+	d.Lag, d.Unit = cal.Units(days), ""
+	return d
+}
+
+// Label returns the type and lag shown on the dependency's edge, or
+// an empty string for a plain finish-to-start dependency
+func (d Dependency) Label() string {
+	if d.Lag != 0 {
+		return fmt.Sprintf("%s%+g%s", d.Type, d.Lag, d.Unit)
+	}
+	if d.Type == FinishToStart {
+		return ""
+	}
+	return d.Type
+}
+
+// String returns the dependency in the syntax ParseDependency reads
+func (d Dependency) String() string {
+	label := d.Label()
+	if label != "" && strings.TrimRight(d.Task, "0123456789") == d.Task {
+		return d.Task + " " + label
+	}
+	return d.Task + label
+}
+
+// earliestStart returns the earliest a task of the given duration
+// can start when its parent runs from start to finish
+func (d Dependency) earliestStart(start, finish, duration float32) float32 {
+	switch d.Type {
+	case StartToStart:
+		return start + d.Lag
+	case FinishToFinish, rollUpType:
+		return finish + d.Lag - duration
+	case StartToFinish:
+		return start + d.Lag - duration
+	}
+	return finish + d.Lag
+}
+
+// latestFinish returns the latest a parent of the given duration can
+// finish when the task runs from start to finish
+func (d Dependency) latestFinish(start, finish, duration float32) float32 {
+	switch d.Type {
+	case StartToStart:
+		return start - d.Lag + duration
+	case FinishToFinish, rollUpType:
+		return finish - d.Lag
+	case StartToFinish:
+		return finish - d.Lag + duration
+	}
+	return start - d.Lag
+}
+
+// dependencyOn returns the dependency in deps on the given parent
+func dependencyOn(deps []Dependency, parent string) Dependency {
+	for _, d := range deps {
+		if d.Task == parent {
+			return d
+		}
+	}
+	return Dependency{Task: parent, Type: FinishToStart}
+}
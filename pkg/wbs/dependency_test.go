@@ -0,0 +1,96 @@
+package wbs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDependency(t *testing.T) {
+	tests := []struct {
+		parent string
+		want   Dependency
+		label  string
+	}{
+		{"1.1", Dependency{Task: "1.1", Type: FinishToStart}, ""},
+		{" 1.2FF ", Dependency{Task: "1.2", Type: FinishToFinish}, "FF"},
+		{"1.1SS+2", Dependency{Task: "1.1", Type: StartToStart, Lag: 2}, "SS+2"},
+		{"1.1SS+2d", Dependency{Task: "1.1", Type: StartToStart, Lag: 2, Unit: DayLag}, "SS+2d"},
+		{"1.1FS+4h", Dependency{Task: "1.1", Type: FinishToStart, Lag: 4, Unit: HourLag}, "FS+4h"},
+		{"design SS-1w", Dependency{Task: "design", Type: StartToStart, Lag: -1, Unit: WeekLag}, "SS-1w"},
+		{"1.1SS+2m", Dependency{Task: "1.1SS+2m", Type: FinishToStart}, ""},
+		{"1.3SF-0.5", Dependency{Task: "1.3", Type: StartToFinish, Lag: -0.5}, "SF-0.5"},
+		{"1.4FS+1", Dependency{Task: "1.4", Type: FinishToStart, Lag: 1}, "FS+1"},
+		{"setup-2", Dependency{Task: "setup-2", Type: FinishToStart}, ""},
+		{"SS", Dependency{Task: "SS", Type: FinishToStart}, ""},
+		{"CSS", Dependency{Task: "CSS", Type: FinishToStart}, ""},
+		{"UI-FF", Dependency{Task: "UI-FF", Type: FinishToStart}, ""},
+		{"design SS+2", Dependency{Task: "design", Type: StartToStart, Lag: 2}, "SS+2"},
+		{"1.1 FF", Dependency{Task: "1.1", Type: FinishToFinish}, "FF"},
+		{"CSS SF", Dependency{Task: "CSS", Type: StartToFinish}, "SF"},
+		{"", Dependency{Task: "", Type: FinishToStart}, ""},
+	}
+	for _, tt := range tests {
+		got := ParseDependency(tt.parent)
+		if got != tt.want {
+			t.Errorf("ParseDependency(%q) = %+v, want %+v", tt.parent, got, tt.want)
+		}
+		if label := got.Label(); label != tt.label {
+			t.Errorf("ParseDependency(%q).Label() = %q, want %q", tt.parent, label, tt.label)
+		}
+	}
+}
+
+func TestGetParentsWithTypes(t *testing.T) {
+	s := Sheet{Parents: "1.1SS+2d, 1.2FF,1.3"}
+	got := s.GetParents()
+	want := []string{"1.1", "1.2", "1.3"}
+	if len(got) != len(want) {
+		t.Fatalf("GetParents() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("GetParents() = %v, want %v", got, want)
+		}
+	}
+	if got := RewriteParents(s.Parents, map[string]string{"1.1": "2.1", "1.3": "2.3"}); got != "2.1SS+2d, 1.2FF, 2.3" {
+		t.Errorf("RewriteParents() = %q", got)
+	}
+	if got := RewriteParents("1.1SS, CSS, design FF", map[string]string{"1.1": "design", "CSS": "2"}); got != "design SS, 2, design FF" {
+		t.Errorf("RewriteParents() = %q", got)
+	}
+}
+
+func TestDependency_InUnits(t *testing.T) {
+	hours := NewCalendar(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC))
+	hours.Unit = HourUnit
+	weeks := NewCalendar(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC))
+	weeks.Unit = WeekUnit
+	tests := []struct {
+		parent string
+		cal    *Calendar
+		want   float32
+	}{
+		{"1SS+2", hours, 2},
+		{"1SS+2d", nil, 2},
+		{"1SS+4h", nil, 0.5},
+		{"1SS+1w", nil, 5},
+		{"1SS+2d", hours, 16},
+		{"1SS+4h", hours, 4},
+		{"1SS+5d", weeks, 1},
+		{"1SS-1w", weeks, -1},
+	}
+	for _, tt := range tests {
+		got := ParseDependency(tt.parent).InUnits(tt.cal)
+		if got.Lag != tt.want || got.Unit != "" {
+			t.Errorf("InUnits(%q) = %+v, want lag %v", tt.parent, got, tt.want)
+		}
+	}
+
+	sheets := WithCalendar([]Sheet{
+		{WBS: "1", Duration: 8},
+		{WBS: "2", Duration: 8, Parents: "1FS+1d"},
+	}, hours)
+	if schedule, _ := ComputeSchedule(sheets); schedule["2"].ES != 16 {
+		t.Errorf("ComputeSchedule() ES after a 1d lag in hours = %v, want 16", schedule["2"].ES)
+	}
+}
@@ -0,0 +1,163 @@
+package wbs
+
+import "sort"
+
+// booking is a period an assignee spends on a task
+type booking struct {
+	Start, End float32
+	Share      float32
+}
+
+// LevelSchedule delays tasks so no assignee is booked for more than
+// their whole time.  Tasks are placed one at a time, choosing among
+// the tasks whose parents are placed the one with the least slack
+// and then the lowest WBS code.  Each starts at the earliest time
+// its dependencies allow that its assignees are free, which may be
+// beyond its slack.
+//
+// The returned schedule keeps the durations of the given schedule,
+// except that summary tasks span the leveled tasks under them.
+// Tasks that are part of a dependency cycle are left where they are.
+// Its late dates and slack are computed backwards from the leveled
+// start of each successor, and Shift records how far each task
+// moved.  The leveled project duration is also returned.
+func LevelSchedule(sheets []Sheet, schedule map[string]*Schedule) (map[string]*Schedule, float32) {
+	tasks, ids, preds, succs := dependencyGraph(sheets)
+	order := topoSort(ids, preds, succs)
+	leveled := make(map[string]*Schedule)
+	for _, id := range ids {
+		node := *schedule[id]
+		leveled[id] = &node
+	}
+	remaining := make(map[string]int)
+	var ready []string
+	for _, id := range order {
+		remaining[id] = len(preds[id])
+		if remaining[id] == 0 {
+			ready = append(ready, id)
+		}
+	}
+
+	bookings := make(map[string][]booking)
+	var duration float32
+	for len(ready) > 0 {
+		sort.SliceStable(ready, func(i, j int) bool {
+			a, b := schedule[ready[i]], schedule[ready[j]]
+			if a.Slack != b.Slack {
+				return a.Slack < b.Slack
+			}
+			return CompareWBS(ready[i], ready[j]) < 0
+		})
+		id := ready[0]
+		ready = ready[1:]
+
+		task, node := tasks[id], leveled[id]
+		if rollUp(node, preds[id], leveled) {
+			node.Shift = node.ES - schedule[id].ES
+		} else {
+			length := node.EF - node.ES
+			var earliest float32
+			for _, d := range preds[id] {
+				parent := leveled[d.Task]
+				if es := d.earliestStart(parent.ES, parent.EF, length); es > earliest {
+					earliest = es
+				}
+			}
+			var assignees []string
+			if !task.IsCompleted() && !task.IsSummary() {
+				assignees = task.GetAssignees()
+			}
+			start := earliestFit(bookings, assignees, task.EffortShare(), earliest, length)
+			for _, a := range assignees {
+				bookings[a] = append(bookings[a], booking{Start: start, End: start + length, Share: task.EffortShare()})
+			}
+			node.Shift = start - node.ES
+			node.ES = start
+			node.EF = start + length
+		}
+		if node.EF > duration {
+			duration = node.EF
+		}
+
+		for _, s := range succs[id] {
+			remaining[s]--
+			if remaining[s] == 0 {
+				ready = append(ready, s)
+			}
+		}
+	}
+
+	for i := len(order) - 1; i >= 0; i-- {
+		id := order[i]
+		node := leveled[id]
+		node.LF = duration
+		for _, s := range succs[id] {
+			child := leveled[s]
+			if lf := dependencyOn(preds[s], id).latestFinish(child.ES, child.EF, node.EF-node.ES); lf < node.LF {
+				node.LF = lf
+			}
+		}
+		node.LS = node.LF - (node.EF - node.ES)
+		node.Slack = node.LS - node.ES
+		node.Critical = node.Slack < CriticalSlack
+	}
+	return leveled, duration
+}
+
+// earliestFit returns the earliest time from earliest on at which
+// every assignee has room for a task of the given length and share.
+// Only the finish of an existing booking can make room, so those
+// are the times tried.
+func earliestFit(bookings map[string][]booking, assignees []string, share, earliest, length float32) float32 {
+	if length <= 0 || len(assignees) == 0 {
+		return earliest
+	}
+	candidates := []float32{earliest}
+	for _, a := range assignees {
+		for _, b := range bookings[a] {
+			if b.End > earliest {
+				candidates = append(candidates, b.End)
+			}
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i] < candidates[j] })
+
+	limit := maxFloat(1, share) + OverloadTolerance
+	for _, start := range candidates {
+		fits := true
+		for _, a := range assignees {
+			if peakLoad(bookings[a], start, start+length)+share > limit {
+				fits = false
+				break
+			}
+		}
+		if fits {
+			return start
+		}
+	}
+	return candidates[len(candidates)-1]
+}
+
+// peakLoad returns the largest total share of the bookings that
+// overlap at any moment between start and end
+func peakLoad(bookings []booking, start, end float32) float32 {
+	points := []float32{start}
+	for _, b := range bookings {
+		if b.Start > start && b.Start < end {
+			points = append(points, b.Start)
+		}
+	}
+	var peak float32
+	for _, p := range points {
+		var load float32
+		for _, b := range bookings {
+			if b.Start <= p && p < b.End {
+				load += b.Share
+			}
+		}
+		if load > peak {
+			peak = load
+		}
+	}
+	return peak
+}
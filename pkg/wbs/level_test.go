@@ -0,0 +1,44 @@
+package wbs
+
+import "testing"
+
+func Test_LevelSchedule(t *testing.T) {
+	sheets := []Sheet{
+		{WBS: "1", Duration: 2, Assignee: "alice"},
+		{WBS: "2", Duration: 1, Assignee: "alice"},
+		{WBS: "3", Duration: 3, Parents: "1", Assignee: "bob"},
+		{WBS: "4", Duration: 4, Assignee: "alice"},
+		{WBS: "5", Duration: 1, Assignee: "carol", Effort: 50},
+		{WBS: "6", Duration: 1, Assignee: "carol", Effort: 50},
+		{WBS: "7", Duration: 2, Assignee: "alice", Status: "Done"},
+	}
+	schedule, _ := ComputeSchedule(sheets)
+	leveled, duration := LevelSchedule(sheets, schedule)
+
+	tests := []struct {
+		wbs      string
+		es, ef   float32
+		shift    float32
+		critical bool
+	}{
+		{"1", 0, 2, 0, true},
+		{"2", 6, 7, 6, true},
+		{"3", 2, 5, 0, false},
+		{"4", 2, 6, 2, false},
+		{"5", 0, 1, 0, false},
+		{"6", 0, 1, 0, false},
+		{"7", 0, 2, 0, false},
+	}
+	for _, tt := range tests {
+		node := leveled[tt.wbs]
+		if node.ES != tt.es || node.EF != tt.ef || node.Shift != tt.shift || node.Critical != tt.critical {
+			t.Errorf("LevelSchedule()[%s] = %+v, want ES %v EF %v Shift %v Critical %v", tt.wbs, node, tt.es, tt.ef, tt.shift, tt.critical)
+		}
+	}
+	if duration != 7 {
+		t.Errorf("LevelSchedule() duration = %v, want 7", duration)
+	}
+	if schedule["2"].ES != 0 {
+		t.Errorf("LevelSchedule() changed the original schedule")
+	}
+}
@@ -0,0 +1,69 @@
+package wbs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Task types
+const (
+	TaskType      = "task"
+	MilestoneType = "milestone"
+	SummaryType   = "summary"
+)
+
+// rawType returns the task's Type, falling back to the Type field
+// GitHub projects and task files set, in lower case
+func (s *Sheet) rawType() string {
+	if t := strings.TrimSpace(s.Type); t != "" {
+		return strings.ToLower(t)
+	}
+	return strings.ToLower(strings.TrimSpace(s.Fields["Type"]))
+}
+
+// HasType returns true if the task's Type or Type field is the given
+// name, e.g. epic
+func (s *Sheet) HasType(name string) bool {
+	return name != "" && s.rawType() == strings.ToLower(name)
+}
+
+// GetType returns the task's type in lower case.  A task without a
+// Type is a milestone when its status is in the milestone category,
+// as older sheets marked them, and a task otherwise.  Other types,
+// such as epic, are scheduled as tasks.
+func (s *Sheet) GetType() string {
+	switch t := s.rawType(); t {
+	case MilestoneType, SummaryType:
+		return t
+	case "":
+		if s.StatusCategory() == MilestoneType {
+			return MilestoneType
+		}
+	}
+	return TaskType
+}
+
+// IsMilestone returns true if the task marks a point in the schedule
+// rather than work
+func (s *Sheet) IsMilestone() bool {
+	return s.GetType() == MilestoneType
+}
+
+// IsSummary returns true if the task only groups the tasks below it
+func (s *Sheet) IsSummary() bool {
+	return s.GetType() == SummaryType
+}
+
+// TargetDate returns the date the milestone is due, or the zero
+// time if it has no target
+func (s *Sheet) TargetDate() (time.Time, error) {
+	if strings.TrimSpace(s.Target) == "" {
+		return time.Time{}, nil
+	}
+	target, err := time.Parse(DateFormat, strings.TrimSpace(s.Target))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid target date %s for %s", s.Target, s.WBS)
+	}
+	return target, nil
+}
@@ -0,0 +1,61 @@
+package wbs
+
+import "testing"
+
+func TestGetType(t *testing.T) {
+	tests := []struct {
+		sheet    Sheet
+		want     string
+		expected float32
+	}{
+		{Sheet{Duration: 2}, TaskType, 2},
+		{Sheet{Type: "Milestone", Duration: 2, Status: "In Progress"}, MilestoneType, 0},
+		{Sheet{Type: "summary", Duration: 5}, SummaryType, 0},
+		{Sheet{Status: "Milestone", Duration: 1}, MilestoneType, 0},
+		{Sheet{Type: "task", Status: "Milestone", Duration: 1}, TaskType, 1},
+		{Sheet{Type: "epic", Duration: 3}, TaskType, 3},
+		{Sheet{Fields: map[string]string{"Type": "Milestone"}, Duration: 1}, MilestoneType, 0},
+		{Sheet{Type: "task", Fields: map[string]string{"Type": "Milestone"}, Duration: 1}, TaskType, 1},
+	}
+	for _, tt := range tests {
+		if got := tt.sheet.GetType(); got != tt.want {
+			t.Errorf("GetType(%+v) = %s, want %s", tt.sheet, got, tt.want)
+		}
+		if got := tt.sheet.Expected(); got != tt.expected {
+			t.Errorf("Expected(%+v) = %v, want %v", tt.sheet, got, tt.expected)
+		}
+	}
+}
+
+func TestHasType(t *testing.T) {
+	tests := []struct {
+		sheet Sheet
+		name  string
+		want  bool
+	}{
+		{Sheet{Type: "epic"}, "epic", true},
+		{Sheet{Fields: map[string]string{"Type": "Epic"}}, "epic", true},
+		{Sheet{Type: "milestone", Fields: map[string]string{"Type": "epic"}}, "epic", false},
+		{Sheet{}, "", false},
+	}
+	for _, tt := range tests {
+		if got := tt.sheet.HasType(tt.name); got != tt.want {
+			t.Errorf("HasType(%+v, %q) = %v, want %v", tt.sheet, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTargetDate(t *testing.T) {
+	s := Sheet{WBS: "2", Target: "2024-03-29"}
+	if got, err := s.TargetDate(); err != nil || got.Format(DateFormat) != "2024-03-29" {
+		t.Errorf("TargetDate() = %v, %v", got, err)
+	}
+	s.Target = ""
+	if got, err := s.TargetDate(); err != nil || !got.IsZero() {
+		t.Errorf("TargetDate() without a target = %v, %v", got, err)
+	}
+	s.Target = "end of March"
+	if _, err := s.TargetDate(); err == nil {
+		t.Errorf("TargetDate(%q) expected an error", s.Target)
+	}
+}
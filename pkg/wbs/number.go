@@ -0,0 +1,152 @@
+package wbs
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// sheetColumns is the header written by WriteFile
+var sheetColumns = []string{"Task", "ID", "Parent", "Title", "Parents", "Duration",
+	"Optimistic", "MostLikely", "Pessimistic", "Status", "Assignee", "Effort", "Type", "Target"}
+
+// key returns the stable ID of the task, or its WBS code when it
+// has none
+func (s *Sheet) key() string {
+	if s.ID != "" {
+		return s.ID
+	}
+	return s.WBS
+}
+
+// AutoNumber assigns the WBS codes from the task hierarchy.  A task's
+// place in the hierarchy comes from its Parent, which names the ID of
+// the task it belongs to, or when that is empty from the indentation
+// of its title: a task belongs to the closest task above it that is
+// indented less.  Children are numbered in input order.
+//
+// The Parents dependencies may name either a task's ID or its old WBS
+// code; both are rewritten to the new code.  The tasks are returned
+// in hierarchy order with their titles trimmed and Parent set, so the
+// result can be written back with WriteFile and numbered again.
+func AutoNumber(sheets []Sheet) ([]Sheet, error) {
+	index := make(map[string]int)
+	for i := range sheets {
+		key := sheets[i].key()
+		if key == "" {
+			continue
+		}
+		if _, ok := index[key]; ok {
+			return nil, fmt.Errorf("duplicate task ID %s", key)
+		}
+		index[key] = i
+	}
+
+	type indented struct {
+		indent int
+		idx    int
+	}
+	var stack []indented
+	children := make(map[int][]int)
+	for i := range sheets {
+		sheet := &sheets[i]
+		title := strings.TrimLeft(sheet.Title, " \t")
+		indent := len(sheet.Title) - len(title)
+		sheet.Title = title
+
+		parent := -1
+		if sheet.Parent != "" {
+			idx, ok := index[sheet.Parent]
+			if !ok {
+				return nil, fmt.Errorf("task %s has an unknown parent %s", sheet.key(), sheet.Parent)
+			}
+			parent = idx
+		} else {
+			for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+				stack = stack[:len(stack)-1]
+			}
+			if len(stack) > 0 {
+				parent = stack[len(stack)-1].idx
+			}
+		}
+		stack = append(stack, indented{indent: indent, idx: i})
+		children[parent] = append(children[parent], i)
+	}
+
+	codes := make(map[string]string)
+	var ordered []Sheet
+	var number func(parent int, parentKey, prefix string, level int)
+	number = func(parent int, parentKey, prefix string, level int) {
+		for n, idx := range children[parent] {
+			sheet := sheets[idx]
+			code := fmt.Sprintf("%s%d", prefix, n+1)
+			if sheet.WBS != "" {
+				codes[sheet.WBS] = code
+			}
+			sheet.WBS = code
+			sheet.Parent = parentKey
+			sheet.Level = level
+			ordered = append(ordered, sheet)
+			number(idx, sheet.key(), code+".", level+1)
+		}
+	}
+	number(-1, "", "", 1)
+	if len(ordered) < len(sheets) {
+		return nil, fmt.Errorf("the Parent column has a loop: %d task(s) never reach the top level", len(sheets)-len(ordered))
+	}
+
+	for _, sheet := range ordered {
+		if sheet.ID != "" {
+			codes[sheet.ID] = sheet.WBS
+		}
+	}
+	for i := range ordered {
+		ordered[i].Parents = RewriteParents(ordered[i].Parents, codes)
+	}
+	return ordered, nil
+}
+
+// RewriteParents replaces the dependencies found in codes with
+// their new value, keeping their types and lags.  Unknown
+// dependencies are kept as they are.
+func RewriteParents(parents string, codes map[string]string) string {
+	if strings.TrimSpace(parents) == "" {
+		return parents
+	}
+	s := Sheet{Parents: parents}
+	var list []string
+	for _, d := range s.GetDependencies() {
+		if code, ok := codes[d.Task]; ok {
+			d.Task = code
+		}
+		list = append(list, d.String())
+	}
+	return strings.Join(list, ", ")
+}
+
+// WriteFile writes the tasks as a CSV spreadsheet that ReadFile can
+// read back
+func WriteFile(w io.Writer, sheets []Sheet) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(sheetColumns); err != nil {
+		return err
+	}
+	for _, s := range sheets {
+		writer.Write([]string{s.WBS, s.ID, s.Parent, s.Title, s.Parents, formatEstimate(s.Duration),
+			formatEstimate(s.Optimistic), formatEstimate(s.MostLikely), formatEstimate(s.Pessimistic),
+			s.Status, s.Assignee, formatEstimate(s.Effort), s.Type, s.Target})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatEstimate formats a duration for the spreadsheet, leaving
+// zero blank
+func formatEstimate(f float32) string {
+	if f == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(float64(f), 'f', -1, 32)
+}
@@ -0,0 +1,94 @@
+package wbs
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func Test_AutoNumber(t *testing.T) {
+	tests := []struct {
+		name    string
+		sheets  []Sheet
+		want    []Sheet
+		wantErr bool
+	}{
+		{
+			name: "Parent column",
+			sheets: []Sheet{
+				{ID: "build", Parent: "dev", Title: "Build", Parents: "design"},
+				{ID: "dev", Title: "Development"},
+				{ID: "design", Parent: "dev", Title: "Design"},
+				{ID: "ship", Title: "Ship", Parents: "build, design"},
+			},
+			want: []Sheet{
+				{WBS: "1", ID: "dev", Title: "Development", Level: 1},
+				{WBS: "1.1", ID: "build", Parent: "dev", Title: "Build", Parents: "1.2", Level: 2},
+				{WBS: "1.2", ID: "design", Parent: "dev", Title: "Design", Level: 2},
+				{WBS: "2", ID: "ship", Title: "Ship", Parents: "1.1, 1.2", Level: 1},
+			},
+		},
+		{
+			name: "Indentation with old codes",
+			sheets: []Sheet{
+				{WBS: "1", Title: "Plan"},
+				{WBS: "1.2", Title: "  Scope"},
+				{WBS: "1.1", Title: "  Budget", Parents: "1.2"},
+				{WBS: "1.1.1", Title: "    Estimate"},
+				{WBS: "2", Title: "Build", Parents: "1.1.1"},
+			},
+			want: []Sheet{
+				{WBS: "1", Title: "Plan", Level: 1},
+				{WBS: "1.1", Parent: "1", Title: "Scope", Level: 2},
+				{WBS: "1.2", Parent: "1", Title: "Budget", Parents: "1.1", Level: 2},
+				{WBS: "1.2.1", Parent: "1.2", Title: "Estimate", Level: 3},
+				{WBS: "2", Title: "Build", Parents: "1.2.1", Level: 1},
+			},
+		},
+		{
+			name:    "Unknown parent",
+			sheets:  []Sheet{{ID: "a", Parent: "b"}},
+			wantErr: true,
+		},
+		{
+			name:    "Parent loop",
+			sheets:  []Sheet{{ID: "a", Parent: "b"}, {ID: "b", Parent: "a"}},
+			wantErr: true,
+		},
+		{
+			name:    "Duplicate ID",
+			sheets:  []Sheet{{ID: "a"}, {ID: "a"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AutoNumber(tt.sheets)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AutoNumber() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AutoNumber() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_WriteFile(t *testing.T) {
+	sheets := []Sheet{
+		{WBS: "1", ID: "dev", Title: "Development, phase 1", Status: "Done"},
+		{WBS: "1.1", ID: "build", Parent: "dev", Title: "Build", Parents: "1.2", Duration: 2.5, Assignee: "alice", Effort: 50},
+		{WBS: "1.2", Title: "Release", Type: "milestone", Target: "2024-03-29"},
+	}
+	buf := bytes.NewBufferString("")
+	if err := WriteFile(buf, sheets); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadFile(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, sheets) {
+		t.Errorf("ReadFile(WriteFile()) = %+v, want %+v", got, sheets)
+	}
+}
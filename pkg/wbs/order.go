@@ -0,0 +1,81 @@
+package wbs
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CompareWBS compares two WBS codes segment by segment, so that
+// 1.2 comes before 1.10.  Segments that are both numbers are
+// compared by value, others as text with numbers first.  A code
+// comes before the codes of its children.  It returns -1, 0 or 1.
+func CompareWBS(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if c := compareSegment(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+	return 0
+}
+
+// compareSegment compares one segment of two WBS codes
+func compareSegment(a, b string) int {
+	an, aErr := strconv.Atoi(strings.TrimSpace(a))
+	bn, bErr := strconv.Atoi(strings.TrimSpace(b))
+	switch {
+	case aErr == nil && bErr == nil:
+		if an != bn {
+			if an < bn {
+				return -1
+			}
+			return 1
+		}
+		return 0
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// SortSheets returns a copy of the tasks sorted by WBS code.  Tasks
+// with the same code keep their input order.
+func SortSheets(sheets []Sheet) []Sheet {
+	sorted := append([]Sheet{}, sheets...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return CompareWBS(sorted[i].WBS, sorted[j].WBS) < 0
+	})
+	return sorted
+}
+
+// FillLevels returns the tasks with an untitled placeholder added
+// before the first child of every WBS level that has no task, so a
+// tree built from the levels attaches each task to its own parent.
+func FillLevels(sheets []Sheet) []Sheet {
+	known := make(map[string]bool)
+	for _, sheet := range sheets {
+		known[sheet.WBS] = true
+	}
+	var filled []Sheet
+	for _, sheet := range sheets {
+		segments := strings.Split(sheet.WBS, ".")
+		for i := 1; i < len(segments); i++ {
+			code := strings.Join(segments[:i], ".")
+			if !known[code] {
+				known[code] = true
+				filled = append(filled, Sheet{WBS: code})
+			}
+		}
+		filled = append(filled, sheet)
+	}
+	return filled
+}
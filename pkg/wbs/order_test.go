@@ -0,0 +1,51 @@
+package wbs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_CompareWBS(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2", "1.10", -1},
+		{"1.10", "1.9", 1},
+		{"1", "1.1", -1},
+		{"2", "1.5", 1},
+		{"1.1", "1.1", 0},
+		{"1.2", "1.A", -1},
+		{"0.99", "1", -1},
+	}
+	for _, tt := range tests {
+		if got := CompareWBS(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareWBS(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func Test_SortSheets(t *testing.T) {
+	sheets := []Sheet{{WBS: "1.10"}, {WBS: "2"}, {WBS: "1.2", Title: "first"}, {WBS: "1"}, {WBS: "1.2", Title: "second"}}
+	want := []Sheet{{WBS: "1"}, {WBS: "1.2", Title: "first"}, {WBS: "1.2", Title: "second"}, {WBS: "1.10"}, {WBS: "2"}}
+	if got := SortSheets(sheets); !reflect.DeepEqual(got, want) {
+		t.Errorf("SortSheets() = %+v, want %+v", got, want)
+	}
+	if sheets[0].WBS != "1.10" {
+		t.Errorf("SortSheets() changed its input")
+	}
+}
+
+func Test_FillLevels(t *testing.T) {
+	sheets := []Sheet{{WBS: "1", Title: "Plan"}, {WBS: "1.2.1", Title: "Scope"}, {WBS: "3.1", Title: "Build"}}
+	want := []Sheet{
+		{WBS: "1", Title: "Plan"},
+		{WBS: "1.2"},
+		{WBS: "1.2.1", Title: "Scope"},
+		{WBS: "3"},
+		{WBS: "3.1", Title: "Build"},
+	}
+	if got := FillLevels(sheets); !reflect.DeepEqual(got, want) {
+		t.Errorf("FillLevels() = %+v, want %+v", got, want)
+	}
+}
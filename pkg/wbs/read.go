@@ -0,0 +1,66 @@
+package wbs
+
+import (
+	"encoding/csv"
+	"io"
+
+	"ghprojects/projects"
+
+	"github.com/jinzhu/copier"
+	"github.com/jszwec/csvutil"
+)
+
+// ReadFile decodes the tasks from a CSV spreadsheet
+func ReadFile(in io.Reader) ([]Sheet, error) {
+	decoder, err := buildDecoder(in)
+	if err != nil {
+		return nil, err
+	}
+	return decodeSheets(decoder)
+}
+
+// decodeSheets reads every task from the decoder
+func decodeSheets(decoder *csvutil.Decoder) ([]Sheet, error) {
+	var sheets []Sheet
+	for {
+		var sheet Sheet
+		if err := decoder.Decode(&sheet); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		sheets = append(sheets, sheet)
+	}
+	return sheets, nil
+}
+
+func buildDecoder(in io.Reader) (*csvutil.Decoder, error) {
+	csvReader := csv.NewReader(in)
+	return csvutil.NewDecoder(csvReader)
+}
+
+// ProjectClient is the part of the GitHub projects client needed
+// to load a board
+type ProjectClient interface {
+	GetProject(org, name string) (*projects.Board, error)
+}
+
+// LoadProject fetches a GitHub project and converts its cards into
+// tasks.  When byRepo is set the WBS is built by repository name.
+func LoadProject(client ProjectClient, org, name string, byRepo bool) (*projects.Board, []Sheet, error) {
+	board, err := client.GetProject(org, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	var cards []*projects.Card
+	if byRepo {
+		cards = board.GetRepoWBS()
+	} else {
+		cards = board.GetWBSCards()
+	}
+	var sheets []Sheet
+	if err := copier.Copy(&sheets, cards); err != nil {
+		return nil, nil, err
+	}
+	return board, sheets, nil
+}
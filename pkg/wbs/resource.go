@@ -0,0 +1,169 @@
+package wbs
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Load periods
+const (
+	DayPeriod  = "day"
+	WeekPeriod = "week"
+)
+
+// WorkWeek is the number of days in a week period without a calendar
+const WorkWeek = 5
+
+// OverloadTolerance is how far above 100% a load can be before it
+// is reported, to allow for rounding
+const OverloadTolerance = 0.001
+
+// GetAssignees splits the assignees and returns them as a list.
+// Tasks without an assignee return an empty list.
+func (s *Sheet) GetAssignees() []string {
+	var assignees []string
+	for _, a := range strings.Split(s.Assignee, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			assignees = append(assignees, a)
+		}
+	}
+	return assignees
+}
+
+// Assignees sets the assignee from a list of names.  copier calls it
+// with the assignees of a GitHub card.
+func (s *Sheet) Assignees(names []string) {
+	s.Assignee = strings.Join(names, ", ")
+}
+
+// EffortShare returns the share of an assignee's time the task
+// takes.  Tasks without an Effort take all of it.
+func (s *Sheet) EffortShare() float32 {
+	if s.Effort <= 0 {
+		return 1
+	}
+	return s.Effort / 100
+}
+
+// PeriodLength returns the number of working days in a load period.
+// A week is the calendar's working week, or WorkWeek without one.
+func PeriodLength(period string, cal *Calendar) float32 {
+	switch {
+	case period != WeekPeriod:
+		return 1
+	case cal == nil:
+		return WorkWeek
+	}
+	return float32(cal.weekLength())
+}
+
+// PeriodStart returns the first working day of the load period with
+// the given index
+func (c *Calendar) PeriodStart(period string, index int) time.Time {
+	return c.workingDay(index * int(PeriodLength(period, c)))
+}
+
+// Allocation is the load of one assignee over one period
+type Allocation struct {
+	Assignee string
+	// Period is the index of the period, counted from the project
+	// start
+	Period int
+	// Load is the share of the period the assignee is booked for;
+	// 1 is fully booked
+	Load float32
+	// Tasks are the WBS IDs of the tasks worked on in the period
+	Tasks []string
+}
+
+// Overloaded returns true if the assignee is booked for more than
+// the whole period
+func (a *Allocation) Overloaded() bool {
+	return a.Load > 1+OverloadTolerance
+}
+
+// ResourceLoad spreads every assigned task's effort over the periods
+// between its start and finish in the schedule.  Completed and
+// summary tasks are left out.  With a calendar the schedule is
+// converted to working days first; without one it is already in
+// days.  The allocations are returned by assignee and period.
+func ResourceLoad(sheets []Sheet, schedule map[string]*Schedule, period string, cal *Calendar) []Allocation {
+	length := PeriodLength(period, cal)
+	loads := make(map[string]map[int]*Allocation)
+	for i := range sheets {
+		sheet := &sheets[i]
+		sched, ok := schedule[sheet.WBS]
+		if !ok || sheet.IsCompleted() || sheet.IsSummary() || sched.EF <= sched.ES {
+			continue
+		}
+		es, ef := sched.ES, sched.EF
+		if cal != nil {
+			es, ef = float32(cal.Days(es)), float32(cal.Days(ef))
+		}
+		first := int(math.Floor(float64(es / length)))
+		last := int(math.Ceil(float64(ef/length))) - 1
+		for _, assignee := range sheet.GetAssignees() {
+			if loads[assignee] == nil {
+				loads[assignee] = make(map[int]*Allocation)
+			}
+			for p := first; p <= last; p++ {
+				start := float32(p) * length
+				overlap := minFloat(ef, start+length) - maxFloat(es, start)
+				if overlap <= 0 {
+					continue
+				}
+				alloc := loads[assignee][p]
+				if alloc == nil {
+					alloc = &Allocation{Assignee: assignee, Period: p}
+					loads[assignee][p] = alloc
+				}
+				alloc.Load += sheet.EffortShare() * overlap / length
+				if !InArray(sheet.WBS, alloc.Tasks) {
+					alloc.Tasks = append(alloc.Tasks, sheet.WBS)
+				}
+			}
+		}
+	}
+
+	var allocations []Allocation
+	for _, periods := range loads {
+		for _, alloc := range periods {
+			allocations = append(allocations, *alloc)
+		}
+	}
+	sort.Slice(allocations, func(i, j int) bool {
+		if allocations[i].Assignee != allocations[j].Assignee {
+			return allocations[i].Assignee < allocations[j].Assignee
+		}
+		return allocations[i].Period < allocations[j].Period
+	})
+	return allocations
+}
+
+// Overallocations returns the allocations where an assignee is
+// booked for more than the whole period
+func Overallocations(allocations []Allocation) []Allocation {
+	var over []Allocation
+	for _, alloc := range allocations {
+		if alloc.Overloaded() {
+			over = append(over, alloc)
+		}
+	}
+	return over
+}
+
+func minFloat(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxFloat(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
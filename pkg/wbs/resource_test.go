@@ -0,0 +1,91 @@
+package wbs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/copier"
+)
+
+func Test_ResourceLoad(t *testing.T) {
+	sheets := []Sheet{
+		{WBS: "1", Duration: 2, Assignee: "alice"},
+		{WBS: "2", Duration: 1, Assignee: "alice, bob", Effort: 50},
+		{WBS: "3", Duration: 1, Parents: "1", Assignee: "bob"},
+		{WBS: "4", Duration: 3, Assignee: "alice", Status: "Done"},
+		{WBS: "5", Duration: 1},
+	}
+	schedule, _ := ComputeSchedule(sheets)
+
+	want := []Allocation{
+		{Assignee: "alice", Period: 0, Load: 1.5, Tasks: []string{"1", "2"}},
+		{Assignee: "alice", Period: 1, Load: 1, Tasks: []string{"1"}},
+		{Assignee: "bob", Period: 0, Load: 0.5, Tasks: []string{"2"}},
+		{Assignee: "bob", Period: 2, Load: 1, Tasks: []string{"3"}},
+	}
+	got := ResourceLoad(sheets, schedule, DayPeriod, nil)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ResourceLoad() = %+v, want %+v", got, want)
+	}
+	if over := Overallocations(got); !reflect.DeepEqual(over, want[:1]) {
+		t.Errorf("Overallocations() = %+v, want %+v", over, want[:1])
+	}
+
+	week := ResourceLoad(sheets, schedule, WeekPeriod, nil)
+	if len(week) != 2 || week[0].Load != 0.5 || week[1].Load != 0.3 {
+		t.Errorf("ResourceLoad() by week = %+v", week)
+	}
+
+	cal := NewCalendar(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC))
+	cal.Unit = HourUnit
+	hours := []Sheet{
+		{WBS: "1", Duration: 16, Assignee: "alice"},
+		{WBS: "2", Duration: 8, Assignee: "alice"},
+	}
+	schedule, _ = ComputeSchedule(hours)
+	want = []Allocation{
+		{Assignee: "alice", Period: 0, Load: 2, Tasks: []string{"1", "2"}},
+		{Assignee: "alice", Period: 1, Load: 1, Tasks: []string{"1"}},
+	}
+	if got := ResourceLoad(hours, schedule, DayPeriod, cal); !reflect.DeepEqual(got, want) {
+		t.Errorf("ResourceLoad() in hours = %+v, want %+v", got, want)
+	}
+	cal.Weekdays = cal.Weekdays[:4]
+	if got := cal.PeriodStart(WeekPeriod, 1); got.Format(DateFormat) != "2024-03-11" {
+		t.Errorf("PeriodStart() = %v, want 2024-03-11", got)
+	}
+}
+
+func TestSheet_GetAssignees(t *testing.T) {
+	s := &Sheet{}
+	s.Assignees([]string{"alice", "bob"})
+	if got := s.GetAssignees(); !reflect.DeepEqual(got, []string{"alice", "bob"}) {
+		t.Errorf("GetAssignees() = %v", got)
+	}
+	if got := (&Sheet{Assignee: " "}).GetAssignees(); got != nil {
+		t.Errorf("GetAssignees() of no one = %v", got)
+	}
+}
+
+// card has the shape of a GitHub project card, which LoadProject
+// copies into sheets
+type card struct {
+	WBS       string
+	Title     string
+	Assignees []string
+}
+
+func TestSheet_AssigneesCopied(t *testing.T) {
+	cards := []*card{
+		{WBS: "1", Title: "Design", Assignees: []string{"alice", "bob"}},
+		{WBS: "2", Title: "Build"},
+	}
+	var sheets []Sheet
+	if err := copier.Copy(&sheets, cards); err != nil {
+		t.Fatal(err)
+	}
+	if len(sheets) != 2 || sheets[0].Assignee != "alice, bob" || sheets[1].Assignee != "" {
+		t.Errorf("copier.Copy() = %+v, want the card assignees", sheets)
+	}
+}
@@ -0,0 +1,247 @@
+package wbs
+
+import "strings"
+
+// CriticalSlack is the largest slack that is still considered
+// zero when deciding if a task is on the critical path
+const CriticalSlack = 0.001
+
+// Schedule holds the critical path values computed for a task
+type Schedule struct {
+	ES       float32
+	EF       float32
+	LS       float32
+	LF       float32
+	Slack    float32
+	Critical bool
+	// PathVariance is the largest sum of task variances along
+	// a critical chain ending with this task
+	PathVariance float32
+	// Shift is how far resource leveling moved the task's start
+	Shift float32
+	// Dates are set when the schedule is placed on a calendar
+	Dates *ScheduleDates
+}
+
+// ComputeSchedule runs a forward and backward pass over the
+// dependency graph built from the tasks' parents and expected
+// durations.  Each dependency's type and lag constrain the ends of
+// the tasks it links, and no task starts before the project does.
+// Parents that are not in the task list are treated as the project
+// start.  It returns the schedule for each task
+// keyed by WBS ID along with the overall project duration.
+func ComputeSchedule(sheets []Sheet) (map[string]*Schedule, float32) {
+	return ScheduleDurations(sheets, (*Sheet).Expected)
+}
+
+// ScheduleDurations is ComputeSchedule with the duration of each
+// task supplied by the given function.
+func ScheduleDurations(sheets []Sheet, taskDuration func(*Sheet) float32) (map[string]*Schedule, float32) {
+	schedule := make(map[string]*Schedule)
+	durations := make(map[string]float32)
+	variances := make(map[string]float32)
+	tasks, ids, preds, succs := dependencyGraph(sheets)
+	for _, id := range ids {
+		schedule[id] = &Schedule{}
+		durations[id] = taskDuration(tasks[id])
+		variances[id] = tasks[id].Variance()
+	}
+
+	order := topoSort(ids, preds, succs)
+
+	var duration float32
+	for _, id := range order {
+		node := schedule[id]
+		if rollUp(node, preds[id], schedule) {
+			durations[id] = node.EF - node.ES
+		} else {
+			for _, d := range preds[id] {
+				parent := schedule[d.Task]
+				if es := d.earliestStart(parent.ES, parent.EF, durations[id]); es > node.ES {
+					node.ES = es
+				}
+			}
+			node.EF = node.ES + durations[id]
+		}
+		if node.EF > duration {
+			duration = node.EF
+		}
+	}
+
+	for i := len(order) - 1; i >= 0; i-- {
+		id := order[i]
+		node := schedule[id]
+		node.LF = duration
+		for _, s := range succs[id] {
+			child := schedule[s]
+			if lf := dependencyOn(preds[s], id).latestFinish(child.LS, child.LF, durations[id]); lf < node.LF {
+				node.LF = lf
+			}
+		}
+		node.LS = node.LF - durations[id]
+		node.Slack = node.LS - node.ES
+		node.Critical = node.Slack < CriticalSlack
+	}
+
+	for _, id := range order {
+		node := schedule[id]
+		if !node.Critical {
+			continue
+		}
+		for _, d := range preds[id] {
+			if IsCriticalDependency(schedule, d, id) && schedule[d.Task].PathVariance > node.PathVariance {
+				node.PathVariance = schedule[d.Task].PathVariance
+			}
+		}
+		node.PathVariance += variances[id]
+	}
+	return schedule, duration
+}
+
+// ProjectVariance returns the variance of the project duration,
+// which is the sum of the task variances along the critical path.
+// When there is more than one critical path the largest is used.
+func ProjectVariance(schedule map[string]*Schedule, duration float32) float32 {
+	var variance float32
+	for _, node := range schedule {
+		if node.Critical && duration-node.EF < CriticalSlack && node.PathVariance > variance {
+			variance = node.PathVariance
+		}
+	}
+	return variance
+}
+
+// IsCriticalEdge returns true if the finish-to-start dependency
+// from parent to task lies on the critical path.  A parent that
+// isn't part of the schedule is treated as the project start.
+func IsCriticalEdge(schedule map[string]*Schedule, parent, task string) bool {
+	return IsCriticalDependency(schedule, Dependency{Task: parent, Type: FinishToStart}, task)
+}
+
+// IsCriticalDependency returns true if the task's dependency lies
+// on the critical path, that is both tasks are critical and the
+// dependency is what sets the task's start
+func IsCriticalDependency(schedule map[string]*Schedule, d Dependency, task string) bool {
+	node, ok := schedule[task]
+	if !ok || !node.Critical {
+		return false
+	}
+	prev, ok := schedule[d.Task]
+	if !ok {
+		return node.ES < CriticalSlack
+	}
+	gap := node.ES - d.earliestStart(prev.ES, prev.EF, node.EF-node.ES)
+	return prev.Critical && gap > -CriticalSlack && gap < CriticalSlack
+}
+
+// dependencyGraph indexes the tasks by WBS ID and links each task to
+// its parents.  When an ID is repeated the first task is used, and
+// parents that aren't in the task list are left out.  A parent
+// listed twice keeps its first dependency.
+//
+// A summary task with tasks under it is linked to them by roll-up
+// dependencies instead of its parents, which are given to each of
+// the tasks under it.
+func dependencyGraph(sheets []Sheet) (map[string]*Sheet, []string, map[string][]Dependency, map[string][]string) {
+	tasks := make(map[string]*Sheet)
+	preds := make(map[string][]Dependency)
+	succs := make(map[string][]string)
+	var ids []string
+	for i := range sheets {
+		if _, ok := tasks[sheets[i].WBS]; ok {
+			continue
+		}
+		ids = append(ids, sheets[i].WBS)
+		tasks[sheets[i].WBS] = &sheets[i]
+	}
+	link := func(id string, d Dependency) {
+		if _, ok := tasks[d.Task]; !ok || InArray(id, succs[d.Task]) {
+			return
+		}
+		preds[id] = append(preds[id], d)
+		succs[d.Task] = append(succs[d.Task], id)
+	}
+
+	members := make(map[string][]string)
+	for _, id := range ids {
+		if !tasks[id].IsSummary() {
+			continue
+		}
+		for _, member := range ids {
+			if IsUnder(member, id) && !tasks[member].IsSummary() {
+				members[id] = append(members[id], member)
+			}
+		}
+	}
+	for _, sheet := range sheets {
+		if len(members[sheet.WBS]) > 0 {
+			continue
+		}
+		for _, d := range sheet.scheduleDependencies() {
+			link(sheet.WBS, d)
+		}
+	}
+	for _, id := range ids {
+		for _, member := range members[id] {
+			for _, d := range tasks[id].scheduleDependencies() {
+				if !IsUnder(d.Task, id) {
+					link(member, d)
+				}
+			}
+			link(id, Dependency{Task: member, Type: rollUpType})
+		}
+	}
+	return tasks, ids, preds, succs
+}
+
+// IsUnder returns true if the WBS code of task is below summary
+func IsUnder(task, summary string) bool {
+	return strings.HasPrefix(task, summary+".")
+}
+
+// rollUp sets the early dates of a summary task to span the tasks
+// under it.  It returns false if the task has none.
+func rollUp(node *Schedule, preds []Dependency, schedule map[string]*Schedule) bool {
+	found := false
+	for _, d := range preds {
+		if d.Type != rollUpType {
+			continue
+		}
+		member := schedule[d.Task]
+		if !found || member.ES < node.ES {
+			node.ES = member.ES
+		}
+		if !found || member.EF > node.EF {
+			node.EF = member.EF
+		}
+		found = true
+	}
+	return found
+}
+
+// topoSort orders the task IDs so that every task comes after
+// all of its predecessors.  Tasks that are part of a cycle can
+// never be ordered and are left out.
+func topoSort(ids []string, preds map[string][]Dependency, succs map[string][]string) []string {
+	var order []string
+	remaining := make(map[string]int)
+	var ready []string
+	for _, id := range ids {
+		remaining[id] = len(preds[id])
+		if remaining[id] == 0 {
+			ready = append(ready, id)
+		}
+	}
+	for len(ready) > 0 {
+		id := ready[0]
+		ready = ready[1:]
+		order = append(order, id)
+		for _, s := range succs[id] {
+			remaining[s]--
+			if remaining[s] == 0 {
+				ready = append(ready, s)
+			}
+		}
+	}
+	return order
+}
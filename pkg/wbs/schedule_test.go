@@ -0,0 +1,133 @@
+package wbs
+
+import "testing"
+
+func TestProjectVariance(t *testing.T) {
+	sheets := []Sheet{
+		{WBS: "1.1", Optimistic: 1, MostLikely: 2, Pessimistic: 3},
+		{WBS: "1.2", Parents: "1.1", Optimistic: 1, MostLikely: 3, Pessimistic: 11},
+		{WBS: "1.3", Parents: "1.1", Optimistic: 0, MostLikely: 1, Pessimistic: 8},
+	}
+	schedule, duration := ComputeSchedule(sheets)
+	if duration != 6 {
+		t.Errorf("ComputeSchedule() duration = %v, want %v", duration, 6)
+	}
+	want := sheets[0].Variance() + sheets[1].Variance()
+	if got := ProjectVariance(schedule, duration); got != want {
+		t.Errorf("ProjectVariance() = %v, want %v", got, want)
+	}
+}
+
+func TestComputeSchedule(t *testing.T) {
+	sheets := []Sheet{
+		{WBS: "1.1", Duration: 2},
+		{WBS: "1.2", Parents: "1.1", Duration: 3},
+		{WBS: "1.3", Parents: "1.1", Duration: 1},
+		{WBS: "1.4", Parents: "1.2, 1.3", Duration: 4},
+	}
+	want := map[string]Schedule{
+		"1.1": {ES: 0, EF: 2, LS: 0, LF: 2, Slack: 0, Critical: true},
+		"1.2": {ES: 2, EF: 5, LS: 2, LF: 5, Slack: 0, Critical: true},
+		"1.3": {ES: 2, EF: 3, LS: 4, LF: 5, Slack: 2},
+		"1.4": {ES: 5, EF: 9, LS: 5, LF: 9, Slack: 0, Critical: true},
+	}
+	schedule, duration := ComputeSchedule(sheets)
+	if duration != 9 {
+		t.Errorf("ComputeSchedule() duration = %v, want %v", duration, 9)
+	}
+	for wbs, w := range want {
+		if got := schedule[wbs]; got == nil || *got != w {
+			t.Errorf("ComputeSchedule() %s = %+v, want %+v", wbs, got, w)
+		}
+	}
+	edges := []struct {
+		parent, task string
+		want         bool
+	}{
+		{"", "1.1", true},
+		{"1.1", "1.2", true},
+		{"1.1", "1.3", false},
+		{"1.3", "1.4", false},
+		{"1.2", "1.4", true},
+	}
+	for _, e := range edges {
+		if got := IsCriticalEdge(schedule, e.parent, e.task); got != e.want {
+			t.Errorf("IsCriticalEdge(%q, %q) = %v, want %v", e.parent, e.task, got, e.want)
+		}
+	}
+}
+
+func TestComputeScheduleDependencyTypes(t *testing.T) {
+	sheets := []Sheet{
+		{WBS: "1", Duration: 4},
+		{WBS: "2", Parents: "1SS+2", Duration: 3},
+		{WBS: "3", Parents: "1FF+1", Duration: 2},
+		{WBS: "4", Parents: "2, 3FS-1", Duration: 1},
+	}
+	want := map[string]Schedule{
+		"1": {ES: 0, EF: 4, LS: 0, LF: 4, Slack: 0, Critical: true},
+		"2": {ES: 2, EF: 5, LS: 2, LF: 5, Slack: 0, Critical: true},
+		"3": {ES: 3, EF: 5, LS: 4, LF: 6, Slack: 1},
+		"4": {ES: 5, EF: 6, LS: 5, LF: 6, Slack: 0, Critical: true},
+	}
+	schedule, duration := ComputeSchedule(sheets)
+	if duration != 6 {
+		t.Errorf("ComputeSchedule() duration = %v, want %v", duration, 6)
+	}
+	for wbs, w := range want {
+		if got := schedule[wbs]; got == nil || *got != w {
+			t.Errorf("ComputeSchedule() %s = %+v, want %+v", wbs, got, w)
+		}
+	}
+	deps := []struct {
+		parent, task string
+		want         bool
+	}{
+		{"1SS+2", "2", true},
+		{"1", "2", false},
+		{"1FF+1", "3", false},
+		{"2", "4", true},
+		{"3FS-1", "4", false},
+	}
+	for _, d := range deps {
+		if got := IsCriticalDependency(schedule, ParseDependency(d.parent), d.task); got != d.want {
+			t.Errorf("IsCriticalDependency(%q, %q) = %v, want %v", d.parent, d.task, got, d.want)
+		}
+	}
+}
+
+func TestComputeScheduleSummary(t *testing.T) {
+	sheets := []Sheet{
+		{WBS: "0", Duration: 1},
+		{WBS: "1", Type: SummaryType, Parents: "0"},
+		{WBS: "1.1", Duration: 5},
+		{WBS: "1.2", Parents: "1.1", Duration: 2},
+		{WBS: "2", Parents: "1", Duration: 3},
+		{WBS: "3", Parents: "1SS", Duration: 1},
+	}
+	want := map[string][2]float32{
+		"0":   {0, 1},
+		"1":   {1, 8},
+		"1.1": {1, 6},
+		"1.2": {6, 8},
+		"2":   {8, 11},
+		"3":   {1, 2},
+	}
+	schedule, duration := ComputeSchedule(sheets)
+	if duration != 11 {
+		t.Errorf("ComputeSchedule() duration = %v, want %v", duration, 11)
+	}
+	for wbs, w := range want {
+		if got := schedule[wbs]; got == nil || got.ES != w[0] || got.EF != w[1] {
+			t.Errorf("ComputeSchedule() %s = %+v, want ES %v EF %v", wbs, got, w[0], w[1])
+		}
+	}
+	if !schedule["1"].Critical || !schedule["1.2"].Critical {
+		t.Errorf("ComputeSchedule() summary 1 and 1.2 should be critical: %+v %+v", schedule["1"], schedule["1.2"])
+	}
+
+	leveled, _ := LevelSchedule(sheets, schedule)
+	if got := leveled["1"]; got.ES != 1 || got.EF != 8 {
+		t.Errorf("LevelSchedule() summary = %+v, want ES 1 EF 8", got)
+	}
+}
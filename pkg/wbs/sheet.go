@@ -0,0 +1,311 @@
+// Package wbs holds the work breakdown structure model: the tasks
+// read from a spreadsheet or GitHub project and the schedule,
+// simulation and validation computed from them.
+package wbs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type Sheet struct {
+	WBS string `csv:"Task"`
+	// ID is a stable name for the task that dependencies can use
+	// in place of the WBS code
+	ID string `csv:"ID,omitempty"`
+	// Parent is the ID of the task this one belongs to when the
+	// WBS codes are assigned by AutoNumber
+	Parent      string  `csv:"Parent,omitempty"`
+	Title       string  `csv:"Title"`
+	Parents     string  `csv:"Parents"`
+	Duration    float32 `csv:"Duration,omitempty"`
+	Optimistic  float32 `csv:"Optimistic,omitempty"`
+	MostLikely  float32 `csv:"MostLikely,omitempty"`
+	Pessimistic float32 `csv:"Pessimistic,omitempty"`
+	Status      string  `csv:"Status"`
+	Assignee    string  `csv:"Assignee,omitempty"`
+	// Effort is the percent of each assignee's time the task takes
+	Effort float32 `csv:"Effort,omitempty"`
+	// Type is task, milestone or summary
+	Type string `csv:"Type,omitempty"`
+	// Target is the date a milestone is due (YYYY-MM-DD)
+	Target   string            `csv:"Target,omitempty"`
+	Labels   []string          `csv:"omitempty"`
+	Fields   map[string]string `csv:"omitempty"`
+	Repo     string            `csv:"omitempty"`
+	Body     string            `csv:"omitempty"`
+	Number   int               `csv:"omitempty"`
+	Schedule Schedule          `csv:"-"`
+	// Level is the depth of the task in the hierarchy, starting at
+	// 1.  When it is zero the level comes from the WBS code.
+	Level int `csv:"-"`
+	// statuses is the status vocabulary set by WithStatuses; the
+	// defaults are used when it is empty
+	statuses []StatusStyle
+	// calendar converts the dependency lags, set by WithCalendar
+	calendar *Calendar
+}
+
+const pertNode = `
+map "%s: %s" as %s %s {
+	Status => %s
+	Early => ES: %0.1f | EF: %0.1f
+	Duration => %0.1f
+	Expected => TE: %0.1f | Var: %0.2f
+	Late  => LS: %0.1f | LF: %0.1f
+	Slack => %0.1f
+}
+`
+const pertDateNode = `
+map "%s: %s" as %s %s {
+	Status => %s
+	Early => ES: %s | EF: %s
+	Duration => %0.1f
+	Expected => TE: %0.1f | Var: %0.2f
+	Late  => LS: %s | LF: %s
+	Slack => %0.1f
+}
+`
+const pertMilestoneNode = `
+map "◆ %s: %s" as %s %s {
+	Status => %s
+	%s => %s
+	Slack => %0.1f
+}
+`
+const criticalBorder = "##[bold]Red"
+const markDownRow = "| %s | %s | %s | %s | %s | %s | %s |"
+
+// GetParents splits the parents and returns
+// them as a list of strings.  The dependency types and lags are
+// left out; GetDependencies returns them.
+func (s *Sheet) GetParents() []string {
+	var parents []string
+	for _, d := range s.GetDependencies() {
+		parents = append(parents, d.Task)
+	}
+	return parents
+}
+
+// HasEstimate returns true if the task has a three-point
+// (optimistic / most likely / pessimistic) estimate
+func (s *Sheet) HasEstimate() bool {
+	return s.Optimistic != 0 || s.MostLikely != 0 || s.Pessimistic != 0
+}
+
+// ValidEstimate returns true if the task's three-point estimate is
+// ordered optimistic <= most likely <= pessimistic
+func (s *Sheet) ValidEstimate() bool {
+	return s.Optimistic <= s.MostLikely && s.MostLikely <= s.Pessimistic
+}
+
+// estimate returns the three-point estimate put in order: the
+// optimistic and pessimistic values are swapped when reversed and
+// the most likely value is clamped between them
+func (s *Sheet) estimate() (float32, float32, float32) {
+	o, m, p := s.Optimistic, s.MostLikely, s.Pessimistic
+	if p < o {
+		o, p = p, o
+	}
+	if m < o {
+		m = o
+	} else if m > p {
+		m = p
+	}
+	return o, m, p
+}
+
+// Expected returns the PERT expected time (O+4M+P)/6 for the
+// task.  Tasks without a three-point estimate use their Duration.
+// Milestones and summary tasks take no time.
+func (s *Sheet) Expected() float32 {
+	if s.IsMilestone() || s.IsSummary() {
+		return 0
+	}
+	if !s.HasEstimate() {
+		return s.Duration
+	}
+	o, m, p := s.estimate()
+	return (o + 4*m + p) / 6
+}
+
+// Variance returns the PERT variance ((P-O)/6)^2 for the task
+func (s *Sheet) Variance() float32 {
+	if !s.HasEstimate() || s.IsMilestone() || s.IsSummary() {
+		return 0
+	}
+	o, _, p := s.estimate()
+	sd := (p - o) / 6
+	return sd * sd
+}
+
+// GetStatusColor returns the PlantUML color of the task's status,
+// e.g. #Thistle, or an empty string if the status has no color
+func (s *Sheet) GetStatusColor() string {
+	color := s.StatusColor()
+	if color == "" {
+		return ""
+	}
+	return "#" + strings.TrimPrefix(color, "#")
+}
+
+// StatusColor returns the color configured for the task's status as
+// a color name or #RRGGBB
+func (s *Sheet) StatusColor() string {
+	if style := s.statusStyle(); style != nil {
+		return style.Color
+	}
+	return ""
+}
+
+// StatusCategory returns the category of the task's status, or an
+// empty string if the status isn't configured
+func (s *Sheet) StatusCategory() string {
+	if style := s.statusStyle(); style != nil {
+		return style.Category
+	}
+	return ""
+}
+
+// IsCompleted returns true if the task's status is in a complete
+// category
+func (s *Sheet) IsCompleted() bool {
+	style := s.statusStyle()
+	return style != nil && style.Complete
+}
+
+// IsActive returns true if the task's status is in an active
+// category
+func (s *Sheet) IsActive() bool {
+	style := s.statusStyle()
+	return style != nil && style.Active
+}
+
+// GetPertNode returns a PlantUML string that represents
+// the task in a PERT chart.  PlantUML maps can't be drawn as
+// diamonds, so milestones have ◆ before their title and show the
+// date they are reached instead of their durations.
+func (s *Sheet) GetPertNode() string {
+	color := s.GetStatusColor()
+	sched := s.Schedule
+	if sched.Critical {
+		color = strings.TrimSpace(color + " " + criticalBorder)
+	}
+	title := strings.ReplaceAll(s.Title, `"`, "")
+	if s.IsMilestone() {
+		label, date := "Day", fmt.Sprintf("%0.1f", sched.ES)
+		if sched.Dates != nil {
+			label, date = "Date", sched.Dates.EF.Format(DateFormat)
+		}
+		return fmt.Sprintf(pertMilestoneNode, s.WBS, title, s.WBS, color, s.Status, label, date, sched.Slack)
+	}
+	if dates := sched.Dates; dates != nil {
+		return fmt.Sprintf(pertDateNode, s.WBS, title, s.WBS, color, s.Status,
+			dates.ES.Format(DateFormat), dates.EF.Format(DateFormat), s.Duration, s.Expected(), s.Variance(),
+			dates.LS.Format(DateFormat), dates.LF.Format(DateFormat), sched.Slack)
+	}
+	return fmt.Sprintf(pertNode, s.WBS, title, s.WBS, color, s.Status,
+		sched.ES, sched.EF, s.Duration, s.Expected(), s.Variance(), sched.LS, sched.LF, sched.Slack)
+}
+
+// GetPertLevel returns the PlantUML PERT node if the WBS task
+// is at least the level specified.  Otherwise an empty string
+// is returned.
+func (s *Sheet) GetPertLevel(lvl int) string {
+	if s.Status == "" {
+		return ""
+	}
+	if s.GetLevel() >= lvl {
+		return s.GetPertNode()
+	}
+	return ""
+}
+
+// GetLevel returns the WBS level for this task.  Tasks read
+// without a hierarchy count the levels of the task ID.
+func (s *Sheet) GetLevel() int {
+	if s.Level > 0 {
+		return s.Level
+	}
+	return strings.Count(s.WBS, ".") + 1
+}
+
+// GetWBS returns a PlantUML WBS line for the task
+func (s *Sheet) GetWBS() string {
+	return s.GetWBSLevel(0)
+}
+
+// GetWBSLevel returns a PlantUML WBS line for the task, one level
+// below the project root.  Tasks below lvl are drawn in a column
+// when lvl is set.
+func (s *Sheet) GetWBSLevel(lvl int) string {
+	str := strings.Repeat("*", s.GetLevel()+1)
+	color := s.GetStatusColor()
+	if len(color) > 0 {
+		str = fmt.Sprintf("%s[%s]", str, color)
+	}
+	if s.GetLevel() > lvl && lvl > 0 {
+		str = str + "_"
+	}
+	return fmt.Sprintf("%s %s", str, s.Label())
+}
+
+// Label returns the WBS code and title of the task, or only the
+// code for an untitled placeholder
+func (s *Sheet) Label() string {
+	if s.Title == "" {
+		return s.WBS
+	}
+	return fmt.Sprintf("%s: %s", s.WBS, s.Title)
+}
+
+// MarkdownRow returns a markdown table row representing the task
+func (s *Sheet) MarkdownRow() string {
+	title := s.Title
+	if s.IsCompleted() {
+		title = "~~" + title + "~~"
+	}
+	return fmt.Sprintf(markDownRow, s.WBS, s.Status, title, s.Parents,
+		strconv.FormatFloat(float64(s.Duration), 'f', 2, 32),
+		strconv.FormatFloat(float64(s.Expected()), 'f', 2, 32),
+		strconv.FormatFloat(float64(s.Variance()), 'f', 2, 32))
+}
+
+// MarkdownDateRow returns MarkdownRow with the calendar dates of
+// the task's schedule added
+func (s *Sheet) MarkdownDateRow() string {
+	dates := []string{"", "", "", ""}
+	if d := s.Schedule.Dates; d != nil {
+		dates = []string{d.ES.Format(DateFormat), d.EF.Format(DateFormat), d.LS.Format(DateFormat), d.LF.Format(DateFormat)}
+	}
+	return fmt.Sprintf("%s %s |", s.MarkdownRow(), strings.Join(dates, " | "))
+}
+
+// MarkdownDateHeader returns the header of the markdown table built
+// from MarkdownDateRow
+func MarkdownDateHeader() string {
+	return strings.Join([]string{
+		fmt.Sprintf(markDownRow+" Start | Finish | Late start | Late finish |", "WBS", "Status", "Task", "Parents", "Duration", "Expected", "Variance"),
+		fmt.Sprintf(markDownRow+" ----- | ------ | ---------- | ----------- |", "---", "------", "----", "-------", "--------", "--------", "--------"),
+	}, "\n")
+}
+
+// MarkdownHeader returns the header of the markdown table built
+// from MarkdownRow
+func MarkdownHeader() string {
+	return strings.Join([]string{
+		fmt.Sprintf(markDownRow, "WBS", "Status", "Task", "Parents", "Duration", "Expected", "Variance"),
+		fmt.Sprintf(markDownRow, "---", "------", "----", "-------", "--------", "--------", "--------"),
+	}, "\n")
+}
+
+// InArray returns true if fld is one of the strings in arr
+func InArray(fld string, arr []string) bool {
+	for _, key := range arr {
+		if fld == key {
+			return true
+		}
+	}
+	return false
+}
@@ -1,4 +1,4 @@
-package main
+package wbs
 
 import (
 	"fmt"
@@ -71,7 +71,7 @@ func TestSheet_GetPertNode(t *testing.T) {
 		{
 			name:   "Get a node",
 			fields: field,
-			want:   fmt.Sprintf(pertNode, field.WBS, field.Title, field.WBS, "", "", field.Duration),
+			want:   fmt.Sprintf(pertNode, field.WBS, field.Title, field.WBS, "", "", 0.0, 0.0, field.Duration, field.Duration, 0.0, 0.0, 0.0, 0.0),
 		},
 	}
 	for _, tt := range tests {
@@ -95,8 +95,9 @@ func TestSheet_GetPertLevel(t *testing.T) {
 		Title    string
 		Parents  string
 		Duration float32
+		Status   string
 	}
-	field := fields{WBS: "1.1", Title: "Test", Parents: "2.1.1, 3.1.1", Duration: 4}
+	field := fields{WBS: "1.1", Title: "Test", Parents: "2.1.1, 3.1.1", Duration: 4, Status: "Waiting"}
 
 	type args struct {
 		lvl int
@@ -111,7 +112,7 @@ func TestSheet_GetPertLevel(t *testing.T) {
 			name:   "Test level 2",
 			fields: field,
 			args:   args{lvl: 2},
-			want:   fmt.Sprintf(pertNode, field.WBS, field.Title, field.WBS, "", "", field.Duration),
+			want:   fmt.Sprintf(pertNode, field.WBS, field.Title, field.WBS, "#Pink", field.Status, 0.0, 0.0, field.Duration, field.Duration, 0.0, 0.0, 0.0, 0.0),
 		},
 		{
 			name:   "Test level 3",
@@ -127,6 +128,7 @@ func TestSheet_GetPertLevel(t *testing.T) {
 				Title:    tt.fields.Title,
 				Parents:  tt.fields.Parents,
 				Duration: tt.fields.Duration,
+				Status:   tt.fields.Status,
 			}
 			if got := s.GetPertLevel(tt.args.lvl); got != tt.want {
 				t.Errorf("Sheet.GetPertLevel() = %v, want %v", got, tt.want)
@@ -135,21 +137,21 @@ func TestSheet_GetPertLevel(t *testing.T) {
 	}
 }
 
-func Test_genMarkdownTableHeader(t *testing.T) {
+func TestMarkdownHeader(t *testing.T) {
 	tests := []struct {
 		name string
 		want string
 	}{
 		{
 			name: "Test Markdown Header",
-			want: `| WBS | Task | Parents | Duration |
-| --- | ---- | ------- | -------- |`,
+			want: `| WBS | Status | Task | Parents | Duration | Expected | Variance |
+| --- | ------ | ---- | ------- | -------- | -------- | -------- |`,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := genMarkdownTableHeader(); got != tt.want {
-				t.Errorf("genMarkdownTableHeader() = %v, want %v", got, tt.want)
+			if got := MarkdownHeader(); got != tt.want {
+				t.Errorf("MarkdownHeader() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -163,6 +165,7 @@ func TestSheet_GetWBS(t *testing.T) {
 		Duration float32
 		Status   string
 	}
+	l1field := fields{WBS: "1", Title: "Top"}
 	l2field := fields{WBS: "1.1", Title: "Test", Parents: "2.1.1, 3.1.1", Duration: 4}
 	l3field := fields{WBS: "1.1.2", Title: "Test2", Parents: "2.1.1, 3.1.1", Duration: 4}
 
@@ -171,15 +174,20 @@ func TestSheet_GetWBS(t *testing.T) {
 		fields fields
 		want   string
 	}{
+		{
+			name:   "Test level 1",
+			fields: l1field,
+			want:   "** 1: Top",
+		},
 		{
 			name:   "Test level 2",
 			fields: l2field,
-			want:   "** 1.1: Test",
+			want:   "*** 1.1: Test",
 		},
 		{
 			name:   "Test level 3",
 			fields: l3field,
-			want:   "*** 1.1.2: Test2",
+			want:   "**** 1.1.2: Test2",
 		},
 	}
 	for _, tt := range tests {
@@ -196,3 +204,35 @@ func TestSheet_GetWBS(t *testing.T) {
 		})
 	}
 }
+
+func TestSheet_Expected(t *testing.T) {
+	tests := []struct {
+		name         string
+		sheet        Sheet
+		wantExpected float32
+		wantVariance float32
+	}{
+		{
+			name:         "Duration only",
+			sheet:        Sheet{WBS: "1.1", Duration: 4},
+			wantExpected: 4,
+			wantVariance: 0,
+		},
+		{
+			name:         "Three point estimate",
+			sheet:        Sheet{WBS: "1.1", Duration: 4, Optimistic: 2, MostLikely: 4, Pessimistic: 12},
+			wantExpected: 5,
+			wantVariance: 25.0 / 9,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sheet.Expected(); got != tt.wantExpected {
+				t.Errorf("Sheet.Expected() = %v, want %v", got, tt.wantExpected)
+			}
+			if got := tt.sheet.Variance(); got != tt.wantVariance {
+				t.Errorf("Sheet.Variance() = %v, want %v", got, tt.wantVariance)
+			}
+		})
+	}
+}
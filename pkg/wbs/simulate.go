@@ -0,0 +1,119 @@
+package wbs
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Distributions used to sample task durations
+const (
+	BetaDistribution       = "beta"
+	TriangularDistribution = "triangular"
+)
+
+// Simulation holds the outcome of a Monte Carlo schedule run
+type Simulation struct {
+	Trials int
+	// Durations is the sorted project duration of every trial
+	Durations []float64
+	// Critical counts the trials in which each task was on the
+	// critical path, keyed by WBS ID
+	Critical map[string]int
+}
+
+// Percentile returns the project duration that p percent of
+// the trials completed within
+func (s *Simulation) Percentile(p int) float64 {
+	if len(s.Durations) == 0 {
+		return 0
+	}
+	idx := int(math.Ceil(float64(p)/100*float64(len(s.Durations)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return s.Durations[idx]
+}
+
+// Criticality returns the fraction of trials in which the task
+// was on the critical path
+func (s *Simulation) Criticality(wbs string) float64 {
+	if s.Trials == 0 {
+		return 0
+	}
+	return float64(s.Critical[wbs]) / float64(s.Trials)
+}
+
+// SampleDuration draws a duration for the task from its three-point
+// estimate using either a triangular or a beta-PERT distribution.
+// Tasks without an estimate always take their expected time.  An
+// estimate out of order is put in order first, as Validate reports.
+// Milestones and summary tasks take no time.
+func SampleDuration(s *Sheet, rng *rand.Rand, distribution string) float32 {
+	if s.IsMilestone() || s.IsSummary() {
+		return 0
+	}
+	if !s.HasEstimate() {
+		return s.Expected()
+	}
+	o32, m32, p32 := s.estimate()
+	o, m, p := float64(o32), float64(m32), float64(p32)
+	if p <= o {
+		return m32
+	}
+	if distribution == TriangularDistribution {
+		u := rng.Float64()
+		c := (m - o) / (p - o)
+		if u < c {
+			return float32(o + math.Sqrt(u*(p-o)*(m-o)))
+		}
+		return float32(p - math.Sqrt((1-u)*(p-o)*(p-m)))
+	}
+	alpha := 1 + 4*(m-o)/(p-o)
+	beta := 1 + 4*(p-m)/(p-o)
+	x := sampleGamma(rng, alpha)
+	y := sampleGamma(rng, beta)
+	return float32(o + x/(x+y)*(p-o))
+}
+
+// sampleGamma draws from a gamma distribution with the given shape
+// (>= 1) and a scale of 1 using the Marsaglia and Tsang method
+func sampleGamma(rng *rand.Rand, shape float64) float64 {
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := rng.Float64()
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
+
+// Simulate samples the task durations and schedules the network
+// the given number of times
+func Simulate(tasks []Sheet, trials int, rng *rand.Rand, distribution string) *Simulation {
+	sim := &Simulation{
+		Trials:   trials,
+		Critical: make(map[string]int),
+	}
+	sample := func(s *Sheet) float32 {
+		return SampleDuration(s, rng, distribution)
+	}
+	for i := 0; i < trials; i++ {
+		schedule, duration := ScheduleDurations(tasks, sample)
+		sim.Durations = append(sim.Durations, float64(duration))
+		for wbs, node := range schedule {
+			if node.Critical {
+				sim.Critical[wbs]++
+			}
+		}
+	}
+	sort.Float64s(sim.Durations)
+	return sim
+}
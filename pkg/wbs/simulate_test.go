@@ -0,0 +1,70 @@
+package wbs
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func Test_SampleDuration(t *testing.T) {
+	sheet := &Sheet{WBS: "1.1", Optimistic: 2, MostLikely: 4, Pessimistic: 10}
+	for _, dist := range []string{"beta", "triangular"} {
+		t.Run(dist, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			for i := 0; i < 1000; i++ {
+				got := SampleDuration(sheet, rng, dist)
+				if got < sheet.Optimistic || got > sheet.Pessimistic {
+					t.Fatalf("SampleDuration() = %v, want between %v and %v", got, sheet.Optimistic, sheet.Pessimistic)
+				}
+			}
+		})
+	}
+	for _, bad := range []*Sheet{
+		{WBS: "1.3", Optimistic: 2, Pessimistic: 8},
+		{WBS: "1.4", Optimistic: 2, MostLikely: 12, Pessimistic: 8},
+		{WBS: "1.5", Optimistic: 8, MostLikely: 4, Pessimistic: 2},
+	} {
+		for _, dist := range []string{"beta", "triangular"} {
+			rng := rand.New(rand.NewSource(1))
+			for i := 0; i < 100; i++ {
+				if got := SampleDuration(bad, rng, dist); got < 2 || got > 8 {
+					t.Fatalf("SampleDuration(%+v) = %v, want between 2 and 8", bad, got)
+				}
+			}
+		}
+	}
+	milestone := &Sheet{WBS: "1.6", Type: MilestoneType, Optimistic: 1, MostLikely: 2, Pessimistic: 3}
+	if got := SampleDuration(milestone, rand.New(rand.NewSource(1)), "beta"); got != 0 {
+		t.Errorf("SampleDuration() of a milestone = %v, want 0", got)
+	}
+	fixed := &Sheet{WBS: "1.2", Duration: 3}
+	if got := SampleDuration(fixed, rand.New(rand.NewSource(1)), "beta"); got != 3 {
+		t.Errorf("SampleDuration() = %v, want %v", got, 3)
+	}
+}
+
+func Test_Simulate(t *testing.T) {
+	tasks := []Sheet{
+		{WBS: "1.1", Duration: 2},
+		{WBS: "1.2", Parents: "1.1", Optimistic: 1, MostLikely: 2, Pessimistic: 3},
+		{WBS: "1.3", Parents: "1.1", Duration: 10},
+	}
+	sim := Simulate(tasks, 200, rand.New(rand.NewSource(42)), "beta")
+	if got := sim.Percentile(50); got != 12 {
+		t.Errorf("Simulation.Percentile(50) = %v, want %v", got, 12)
+	}
+	wantCrit := map[string]float64{"1.1": 1, "1.2": 0, "1.3": 1}
+	for wbs, want := range wantCrit {
+		if got := sim.Criticality(wbs); got != want {
+			t.Errorf("Simulation.Criticality(%s) = %v, want %v", wbs, got, want)
+		}
+	}
+
+	uncertain := []Sheet{{WBS: "1.1", Optimistic: 1, MostLikely: 5, Pessimistic: 20}}
+	first := Simulate(uncertain, 200, rand.New(rand.NewSource(42)), "triangular")
+	again := Simulate(uncertain, 200, rand.New(rand.NewSource(42)), "triangular")
+	for i := range first.Durations {
+		if first.Durations[i] != again.Durations[i] {
+			t.Fatalf("Simulate() is not reproducible with the same seed")
+		}
+	}
+}
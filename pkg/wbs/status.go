@@ -0,0 +1,90 @@
+package wbs
+
+import (
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// StatusStyle maps a set of status names to a category and says how
+// tasks with those statuses are drawn
+type StatusStyle struct {
+	Category string `yaml:"category" description:"Name of the status category, e.g. in progress"`
+	// Statuses are matched without case.  A name ending in * matches
+	// every status starting with it.
+	Statuses []string `yaml:"statuses" description:"Status names in the category; a trailing * matches a prefix"`
+	Color    string   `yaml:"color,omitempty" description:"Color name or #RRGGBB used to fill the tasks"`
+	Complete bool     `yaml:"complete,omitempty" description:"Tasks in the category are complete"`
+	Active   bool     `yaml:"active,omitempty" description:"Tasks in the category are being worked on"`
+	Label    string   `yaml:"label,omitempty" description:"Legend label; categories without one are left out of the legend"`
+}
+
+// DefaultStatuses is the status vocabulary used when none is
+// configured
+var DefaultStatuses = []StatusStyle{
+	{Category: "complete", Statuses: []string{"complete*", "done"}, Color: "Thistle", Complete: true, Label: "Complete"},
+	{Category: "in progress", Statuses: []string{"in progress", "under review"}, Color: "DarkSeaGreen", Active: true, Label: "In Process"},
+	{Category: "waiting", Statuses: []string{"waiting"}, Color: "Pink", Label: "Waiting on Someone"},
+	{Category: "blocked", Statuses: []string{"blocked", "stalled"}, Color: "Red", Label: "Blocked / Stalled"},
+	{Category: "milestone", Statuses: []string{"milestone"}, Color: "Orange", Label: "Milestone"},
+}
+
+// StatusesOrDefault returns the status vocabulary, or the defaults
+// when it is empty
+func StatusesOrDefault(styles []StatusStyle) []StatusStyle {
+	if len(styles) == 0 {
+		return DefaultStatuses
+	}
+	return styles
+}
+
+// WithStatuses returns a copy of the tasks that use the given status
+// vocabulary.  An empty list uses the defaults.
+func WithStatuses(sheets []Sheet, styles []StatusStyle) []Sheet {
+	tasks := append([]Sheet{}, sheets...)
+	for i := range tasks {
+		tasks[i].statuses = styles
+	}
+	return tasks
+}
+
+// ReadStatuses decodes a status vocabulary from a YAML document with
+// a statuses list.  Unknown keys are rejected.
+func ReadStatuses(in io.Reader) ([]StatusStyle, error) {
+	var file struct {
+		Statuses []StatusStyle `yaml:"statuses"`
+	}
+	decoder := yaml.NewDecoder(in)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return file.Statuses, nil
+}
+
+// LookupStatus returns the style of the first category in the
+// vocabulary that matches the status, or nil if none does.  An empty
+// vocabulary uses the defaults.
+func LookupStatus(styles []StatusStyle, status string) *StatusStyle {
+	status = strings.ToLower(strings.TrimSpace(status))
+	if status == "" {
+		return nil
+	}
+	statuses := StatusesOrDefault(styles)
+	for i := range statuses {
+		for _, name := range statuses[i].Statuses {
+			name = strings.ToLower(name)
+			if name == status || (strings.HasSuffix(name, "*") && strings.HasPrefix(status, strings.TrimSuffix(name, "*"))) {
+				return &statuses[i]
+			}
+		}
+	}
+	return nil
+}
+
+// statusStyle returns the style of the task's status in its
+// vocabulary
+func (s *Sheet) statusStyle() *StatusStyle {
+	return LookupStatus(s.statuses, s.Status)
+}
@@ -0,0 +1,72 @@
+package wbs
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_ReadStatuses(t *testing.T) {
+	styles, err := ReadStatuses(strings.NewReader(`statuses:
+  - category: complete
+    statuses: [Shipped, "done*"]
+    color: "#C0C0C0"
+    complete: true
+    label: Shipped
+  - category: in progress
+    statuses: [QA]
+    color: LightBlue
+    active: true
+    label: In QA
+  - category: on hold
+    statuses: [On Hold]
+  - category: doing
+    statuses: [Doing]
+    active: true
+`))
+	if err != nil {
+		t.Fatalf("ReadStatuses() error = %v", err)
+	}
+
+	tests := []struct {
+		status    string
+		color     string
+		category  string
+		completed bool
+		active    bool
+	}{
+		{"shipped", "#C0C0C0", "complete", true, false},
+		{"Done - verified", "#C0C0C0", "complete", true, false},
+		{"QA", "#LightBlue", "in progress", false, true},
+		{"On Hold", "", "on hold", false, false},
+		{"Doing", "", "doing", false, true},
+		{"In Progress", "", "", false, false},
+		{"", "", "", false, false},
+	}
+	for _, tt := range tests {
+		s := &WithStatuses([]Sheet{{Status: tt.status}}, styles)[0]
+		if got := s.GetStatusColor(); got != tt.color {
+			t.Errorf("GetStatusColor(%q) = %q, want %q", tt.status, got, tt.color)
+		}
+		if got := s.StatusCategory(); got != tt.category {
+			t.Errorf("StatusCategory(%q) = %q, want %q", tt.status, got, tt.category)
+		}
+		if got := s.IsCompleted(); got != tt.completed {
+			t.Errorf("IsCompleted(%q) = %v, want %v", tt.status, got, tt.completed)
+		}
+		if got := s.IsActive(); got != tt.active {
+			t.Errorf("IsActive(%q) = %v, want %v", tt.status, got, tt.active)
+		}
+	}
+
+	s := &WithStatuses([]Sheet{{WBS: "1", Title: "Release", Status: "Shipped"}}, styles)[0]
+	if row := s.MarkdownRow(); !strings.Contains(row, "~~Release~~") {
+		t.Errorf("MarkdownRow() = %s, want the title struck through", row)
+	}
+	if (&Sheet{Status: "Shipped"}).IsCompleted() {
+		t.Errorf("IsCompleted() of a task without the vocabulary should use the defaults")
+	}
+
+	if _, err := ReadStatuses(strings.NewReader("statuses:\n  - name: x\n")); err == nil {
+		t.Errorf("ReadStatuses() should reject unknown keys")
+	}
+}
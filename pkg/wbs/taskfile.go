@@ -0,0 +1,147 @@
+package wbs
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Input formats
+const (
+	CSVFormat  = "csv"
+	XLSXFormat = "xlsx"
+	YAMLFormat = "yaml"
+	JSONFormat = "json"
+)
+
+// InputFormat returns the input format implied by a file extension,
+// or an empty string when the extension doesn't name one
+func InputFormat(ext string) string {
+	switch strings.ToLower(ext) {
+	case ".csv":
+		return CSVFormat
+	case XLSXExt:
+		return XLSXFormat
+	case ".yaml", ".yml":
+		return YAMLFormat
+	case ".json":
+		return JSONFormat
+	}
+	return ""
+}
+
+// taskFile is the layout of a YAML or JSON task file
+type taskFile struct {
+	Tasks []taskEntry `yaml:"tasks"`
+}
+
+// taskEntry is one task of a task file.  The keys match the CSV
+// columns: task is the WBS code and id a stable name parents can use
+// instead of it.  Children are listed under tasks and take their WBS
+// code from their position when they have no task code.
+type taskEntry struct {
+	Task        string            `yaml:"task"`
+	ID          string            `yaml:"id"`
+	Title       string            `yaml:"title"`
+	Parents     stringList        `yaml:"parents"`
+	Duration    float32           `yaml:"duration"`
+	Optimistic  float32           `yaml:"optimistic"`
+	MostLikely  float32           `yaml:"mostLikely"`
+	Pessimistic float32           `yaml:"pessimistic"`
+	Status      string            `yaml:"status"`
+	Assignee    string            `yaml:"assignee"`
+	Effort      float32           `yaml:"effort"`
+	Type        string            `yaml:"type"`
+	Target      string            `yaml:"target"`
+	Labels      []string          `yaml:"labels"`
+	Fields      map[string]string `yaml:"fields"`
+	Repo        string            `yaml:"repo"`
+	Number      int               `yaml:"number"`
+	Body        string            `yaml:"body"`
+	Tasks       []taskEntry       `yaml:"tasks"`
+}
+
+// stringList is a list of strings that can also be written as a
+// single comma separated string
+type stringList []string
+
+// UnmarshalYAML accepts either a sequence or a scalar
+func (l *stringList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.SequenceNode {
+		var list []string
+		if err := node.Decode(&list); err != nil {
+			return err
+		}
+		*l = list
+		return nil
+	}
+	var value string
+	if err := node.Decode(&value); err != nil {
+		return err
+	}
+	*l = nil
+	if value != "" {
+		*l = stringList{value}
+	}
+	return nil
+}
+
+// ReadTasks decodes the tasks from a YAML or JSON task file.  Keys
+// that aren't task settings are rejected.  Tasks are returned in
+// the order they appear, each followed by its children.
+func ReadTasks(in io.Reader) ([]Sheet, error) {
+	var file taskFile
+	decoder := yaml.NewDecoder(in)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && err != io.EOF {
+		return nil, err
+	}
+	var sheets []Sheet
+	flattenTasks(file.Tasks, "", 1, &sheets)
+	codes := make(map[string]string)
+	for _, sheet := range sheets {
+		if sheet.ID != "" {
+			codes[sheet.ID] = sheet.WBS
+		}
+	}
+	for i := range sheets {
+		sheets[i].Parents = RewriteParents(sheets[i].Parents, codes)
+	}
+	return sheets, nil
+}
+
+// flattenTasks appends the tasks and their children to sheets.
+// Tasks without a task code are numbered from their parent's WBS
+// code.
+func flattenTasks(entries []taskEntry, prefix string, level int, sheets *[]Sheet) {
+	for i, entry := range entries {
+		code := entry.Task
+		if code == "" {
+			code = fmt.Sprintf("%s%d", prefix, i+1)
+		}
+		*sheets = append(*sheets, Sheet{
+			WBS:         code,
+			ID:          entry.ID,
+			Level:       level,
+			Title:       entry.Title,
+			Parents:     strings.Join(entry.Parents, ", "),
+			Duration:    entry.Duration,
+			Optimistic:  entry.Optimistic,
+			MostLikely:  entry.MostLikely,
+			Pessimistic: entry.Pessimistic,
+			Status:      entry.Status,
+			Assignee:    entry.Assignee,
+			Effort:      entry.Effort,
+			Type:        entry.Type,
+			Target:      entry.Target,
+			Labels:      entry.Labels,
+			Fields:      entry.Fields,
+			Repo:        entry.Repo,
+			Number:      entry.Number,
+			Body:        entry.Body,
+		})
+		flattenTasks(entry.Tasks, code+".", level+1, sheets)
+	}
+}
@@ -0,0 +1,80 @@
+package wbs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_ReadTasks(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		want    []Sheet
+		wantErr bool
+	}{
+		{
+			name: "Nested YAML",
+			doc: `tasks:
+  - title: Design
+    tasks:
+      - title: Sketch
+        id: sketch
+        duration: 2
+        assignee: alice
+        labels: [ux]
+      - task: "1.5"
+        title: Review
+        parents: 1.1
+        optimistic: 1
+        mostLikely: 2
+        pessimistic: 6
+        fields:
+          Type: epic
+        body: |
+          Line one
+          Line two
+  - task: "7"
+    title: Build
+    parents: [sketch, "1.5"]
+    status: In Progress
+`,
+			want: []Sheet{
+				{WBS: "1", Title: "Design", Level: 1},
+				{WBS: "1.1", ID: "sketch", Title: "Sketch", Duration: 2, Assignee: "alice", Labels: []string{"ux"}, Level: 2},
+				{WBS: "1.5", Title: "Review", Parents: "1.1", Level: 2, Optimistic: 1, MostLikely: 2, Pessimistic: 6,
+					Fields: map[string]string{"Type": "epic"}, Body: "Line one\nLine two\n"},
+				{WBS: "7", Title: "Build", Level: 1, Parents: "1.1, 1.5", Status: "In Progress"},
+			},
+		},
+		{
+			name: "JSON",
+			doc:  `{"tasks": [{"title": "Plan", "tasks": [{"title": "Scope", "duration": 1.5}]}]}`,
+			want: []Sheet{
+				{WBS: "1", Title: "Plan", Level: 1},
+				{WBS: "1.1", Title: "Scope", Duration: 1.5, Level: 2},
+			},
+		},
+		{
+			name: "Empty file",
+			doc:  "",
+			want: nil,
+		},
+		{
+			name:    "Unknown key",
+			doc:     "tasks:\n  - title: Plan\n    owner: bob\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadTasks(strings.NewReader(tt.doc))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadTasks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadTasks() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
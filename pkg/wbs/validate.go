@@ -0,0 +1,132 @@
+package wbs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Validate checks the task list for problems that would produce
+// a broken chart: duplicate task IDs, parents that don't exist,
+// dependency cycles, WBS codes whose parent level is missing and
+// three-point estimates that are incomplete or out of order.
+// Each problem is returned as a readable message.
+func Validate(sheets []Sheet) []string {
+	var problems []string
+	ids := make(map[string]int)
+	var order []string
+	for _, sheet := range sheets {
+		if ids[sheet.WBS] == 0 {
+			order = append(order, sheet.WBS)
+		}
+		ids[sheet.WBS]++
+	}
+
+	for _, id := range order {
+		if ids[id] > 1 {
+			problems = append(problems, fmt.Sprintf("duplicate task: %s appears %d times", id, ids[id]))
+		}
+	}
+
+	for _, sheet := range sheets {
+		for _, p := range sheet.GetParents() {
+			if p != "" && ids[p] == 0 && lagUnitPattern.MatchString(p) {
+				problems = append(problems, fmt.Sprintf("invalid lag: %s depends on %s but a lag's unit must be h, d or w", sheet.WBS, p))
+			} else if p != "" && ids[p] == 0 {
+				problems = append(problems, fmt.Sprintf("unknown parent: %s depends on %s which does not exist", sheet.WBS, p))
+			}
+		}
+	}
+
+	for _, id := range order {
+		idx := strings.LastIndex(id, ".")
+		if idx < 0 {
+			continue
+		}
+		if parent := id[:idx]; ids[parent] == 0 {
+			problems = append(problems, fmt.Sprintf("missing parent: %s has no parent task %s", id, parent))
+		}
+	}
+
+	for _, sheet := range sheets {
+		if sheet.HasEstimate() && !sheet.ValidEstimate() {
+			problems = append(problems, fmt.Sprintf("invalid estimate: %s needs optimistic <= most likely <= pessimistic, got %g, %g, %g",
+				sheet.WBS, sheet.Optimistic, sheet.MostLikely, sheet.Pessimistic))
+		}
+	}
+
+	for _, cycle := range FindCycles(sheets) {
+		problems = append(problems, fmt.Sprintf("cycle: %s", strings.Join(cycle, " -> ")))
+	}
+	return problems
+}
+
+// FindCycles returns every dependency loop found with a depth first
+// search of the parent graph.  Each loop is listed as the path of
+// task IDs, ending with the task it started from.
+func FindCycles(sheets []Sheet) [][]string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	var cycles [][]string
+	var ids []string
+	succs := make(map[string][]string)
+	known := make(map[string]bool)
+	for _, sheet := range sheets {
+		if !known[sheet.WBS] {
+			ids = append(ids, sheet.WBS)
+			known[sheet.WBS] = true
+		}
+	}
+	for _, sheet := range sheets {
+		for _, p := range sheet.GetParents() {
+			if known[p] {
+				succs[p] = append(succs[p], sheet.WBS)
+			}
+		}
+	}
+
+	state := make(map[string]int)
+	var stack []string
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = visiting
+		stack = append(stack, id)
+		for _, next := range succs[id] {
+			switch state[next] {
+			case unvisited:
+				visit(next)
+			case visiting:
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == next {
+						cycle := append([]string{}, stack[i:]...)
+						cycles = append(cycles, append(cycle, next))
+						break
+					}
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = done
+	}
+	for _, id := range ids {
+		if state[id] == unvisited {
+			visit(id)
+		}
+	}
+	return cycles
+}
+
+// ValidationReport formats the problems found by Validate
+func ValidationReport(problems []string) string {
+	if len(problems) == 0 {
+		return "No problems found\n"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Found %d problem(s):\n", len(problems))
+	for _, p := range problems {
+		fmt.Fprintf(&b, "  %s\n", p)
+	}
+	return b.String()
+}
@@ -0,0 +1,82 @@
+package wbs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		sheets []Sheet
+		want   []string
+	}{
+		{
+			name: "Valid tasks",
+			sheets: []Sheet{
+				{WBS: "1"},
+				{WBS: "1.1"},
+				{WBS: "1.2", Parents: "1.1"},
+			},
+			want: nil,
+		},
+		{
+			name: "Duplicate and unknown",
+			sheets: []Sheet{
+				{WBS: "1"},
+				{WBS: "1.1", Parents: "9.9"},
+				{WBS: "1.1"},
+			},
+			want: []string{
+				"duplicate task: 1.1 appears 2 times",
+				"unknown parent: 1.1 depends on 9.9 which does not exist",
+			},
+		},
+		{
+			name: "Missing WBS parent",
+			sheets: []Sheet{
+				{WBS: "1"},
+				{WBS: "1.2.3"},
+			},
+			want: []string{"missing parent: 1.2.3 has no parent task 1.2"},
+		},
+		{
+			name: "Cycle",
+			sheets: []Sheet{
+				{WBS: "1"},
+				{WBS: "1.1", Parents: "1.3"},
+				{WBS: "1.2", Parents: "1.1"},
+				{WBS: "1.3", Parents: "1.2"},
+			},
+			want: []string{"cycle: 1.1 -> 1.2 -> 1.3 -> 1.1"},
+		},
+		{
+			name: "Lag with a unit",
+			sheets: []Sheet{
+				{WBS: "1"},
+				{WBS: "2", Parents: "1SS+2d"},
+				{WBS: "3", Parents: "1SS+2m"},
+			},
+			want: []string{"invalid lag: 3 depends on 1SS+2m but a lag's unit must be h, d or w"},
+		},
+		{
+			name: "Estimates",
+			sheets: []Sheet{
+				{WBS: "1", Optimistic: 0, MostLikely: 0, Pessimistic: 4},
+				{WBS: "2", Optimistic: 2, Pessimistic: 8},
+				{WBS: "3", Optimistic: 5, MostLikely: 4, Pessimistic: 3},
+			},
+			want: []string{
+				"invalid estimate: 2 needs optimistic <= most likely <= pessimistic, got 2, 0, 8",
+				"invalid estimate: 3 needs optimistic <= most likely <= pessimistic, got 5, 4, 3",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Validate(tt.sheets); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
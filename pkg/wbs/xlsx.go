@@ -0,0 +1,310 @@
+package wbs
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jszwec/csvutil"
+)
+
+// XLSXExt is the file extension of Excel workbooks
+const XLSXExt = ".xlsx"
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		ID   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxText is a string item: plain text or runs of rich text
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	text := t.T
+	for _, r := range t.Runs {
+		text += r.T
+	}
+	return text
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+// xlsxStyles holds the number formats cells are styled with
+type xlsxStyles struct {
+	NumFmts []struct {
+		ID   int    `xml:"numFmtId,attr"`
+		Code string `xml:"formatCode,attr"`
+	} `xml:"numFmts>numFmt"`
+	CellXfs []struct {
+		NumFmtID int `xml:"numFmtId,attr"`
+	} `xml:"cellXfs>xf"`
+}
+
+// dateStyles reports which cell styles format numbers as dates
+func (s xlsxStyles) dateStyles() []bool {
+	codes := map[int]string{}
+	for _, f := range s.NumFmts {
+		codes[f.ID] = f.Code
+	}
+	dates := make([]bool, len(s.CellXfs))
+	for i, xf := range s.CellXfs {
+		if code, ok := codes[xf.NumFmtID]; ok {
+			dates[i] = isDateFormat(code)
+		} else {
+			// The built in date formats, e.g. m/d/yyyy and d-mmm-yy
+			dates[i] = (xf.NumFmtID >= 14 && xf.NumFmtID <= 17) || xf.NumFmtID == 22
+		}
+	}
+	return dates
+}
+
+// isDateFormat reports whether a number format code shows a date:
+// it has a day or year outside quoted text and [bracketed] sections
+func isDateFormat(code string) bool {
+	quoted, bracketed := false, false
+	for _, r := range strings.ToLower(code) {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case quoted:
+		case r == '[':
+			bracketed = true
+		case r == ']':
+			bracketed = false
+		case bracketed:
+		case r == 'd' || r == 'y':
+			return true
+		}
+	}
+	return false
+}
+
+// xlsxEpoch is day zero of the 1900 date system, which counts the
+// phantom 29 February 1900 so later serials line up
+var xlsxEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// xlsxDate returns a date serial such as 46032 as YYYY-MM-DD
+func xlsxDate(serial string) (string, bool) {
+	days, err := strconv.ParseFloat(serial, 64)
+	if err != nil || days < 0 {
+		return "", false
+	}
+	return xlsxEpoch.AddDate(0, 0, int(days)).Format(DateFormat), true
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref    string   `xml:"r,attr"`
+			Type   string   `xml:"t,attr"`
+			Style  int      `xml:"s,attr"`
+			Value  string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// ReadXLSXFile decodes the tasks from a sheet of the Excel workbook
+// at path.  An empty sheet name reads the first sheet.
+func ReadXLSXFile(path, sheet string) ([]Sheet, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return ReadXLSX(file, info.Size(), sheet)
+}
+
+// ReadXLSX decodes the tasks from a sheet of an Excel workbook.  The
+// first row names the columns, which are matched to the Sheet fields
+// the same way as a CSV header.  Text cells are read exactly as
+// entered so WBS codes like 1.10 aren't turned into numbers, and
+// date cells are read as YYYY-MM-DD.
+func ReadXLSX(in io.ReaderAt, size int64, sheet string) ([]Sheet, error) {
+	archive, err := zip.NewReader(in, size)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := readXLSXRows(archive, sheet)
+	if err != nil {
+		return nil, err
+	}
+	decoder, err := csvutil.NewDecoder(&rowReader{rows: rows})
+	if err != nil {
+		return nil, err
+	}
+	return decodeSheets(decoder)
+}
+
+// rowReader hands the rows of a worksheet to the CSV decoder
+type rowReader struct {
+	rows [][]string
+}
+
+func (r *rowReader) Read() ([]string, error) {
+	if len(r.rows) == 0 {
+		return nil, io.EOF
+	}
+	row := r.rows[0]
+	r.rows = r.rows[1:]
+	return row, nil
+}
+
+// readXLSXRows returns the cell text of every non-empty row of the
+// named sheet.  Numbers styled as dates are read as YYYY-MM-DD.  All
+// the rows are padded to the same width.
+func readXLSXRows(archive *zip.Reader, name string) ([][]string, error) {
+	sheetPath, err := xlsxSheetPath(archive, name)
+	if err != nil {
+		return nil, err
+	}
+	var shared xlsxSharedStrings
+	if err := readXLSXPart(archive, "xl/sharedStrings.xml", &shared); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var styles xlsxStyles
+	if err := readXLSXPart(archive, "xl/styles.xml", &styles); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	dates := styles.dateStyles()
+	var worksheet xlsxWorksheet
+	if err := readXLSXPart(archive, sheetPath, &worksheet); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	width := 0
+	for _, row := range worksheet.Rows {
+		var cells []string
+		empty := true
+		for i, cell := range row.Cells {
+			col := columnIndex(cell.Ref)
+			if col < 0 {
+				col = i
+			}
+			text := cell.Value
+			switch cell.Type {
+			case "s":
+				var idx int
+				if _, err := fmt.Sscan(cell.Value, &idx); err != nil || idx < 0 || idx >= len(shared.Items) {
+					return nil, fmt.Errorf("%s: cell %s has an invalid shared string %s", name, cell.Ref, cell.Value)
+				}
+				text = shared.Items[idx].String()
+			case "inlineStr":
+				text = cell.Inline.String()
+			case "", "n":
+				if cell.Style >= 0 && cell.Style < len(dates) && dates[cell.Style] {
+					if date, ok := xlsxDate(cell.Value); ok {
+						text = date
+					}
+				}
+			}
+			for len(cells) <= col {
+				cells = append(cells, "")
+			}
+			cells[col] = text
+			if text != "" {
+				empty = false
+			}
+		}
+		if empty {
+			continue
+		}
+		if len(cells) > width {
+			width = len(cells)
+		}
+		rows = append(rows, cells)
+	}
+	for i := range rows {
+		for len(rows[i]) < width {
+			rows[i] = append(rows[i], "")
+		}
+	}
+	return rows, nil
+}
+
+// xlsxSheetPath returns the path in the archive of the named sheet
+func xlsxSheetPath(archive *zip.Reader, name string) (string, error) {
+	var workbook xlsxWorkbook
+	if err := readXLSXPart(archive, "xl/workbook.xml", &workbook); err != nil {
+		return "", err
+	}
+	var rels xlsxRelationships
+	if err := readXLSXPart(archive, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", fmt.Errorf("the workbook has no sheets")
+	}
+	id := ""
+	if name == "" {
+		id = workbook.Sheets[0].ID
+	}
+	for _, sheet := range workbook.Sheets {
+		if sheet.Name == name {
+			id = sheet.ID
+		}
+	}
+	if id == "" {
+		return "", fmt.Errorf("the workbook has no sheet named %s", name)
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID != id {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return "", fmt.Errorf("the workbook has no part for sheet %s", name)
+}
+
+// readXLSXPart decodes an XML part of the workbook archive
+func readXLSXPart(archive *zip.Reader, name string, v interface{}) error {
+	part, err := archive.Open(name)
+	if err != nil {
+		return err
+	}
+	defer part.Close()
+	return xml.NewDecoder(part).Decode(v)
+}
+
+// columnIndex returns the zero based column of a cell reference
+// such as C12, or -1 if the reference has no column
+func columnIndex(ref string) int {
+	col := 0
+	for _, r := range strings.ToUpper(ref) {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A') + 1
+	}
+	return col - 1
+}
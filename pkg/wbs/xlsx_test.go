@@ -0,0 +1,116 @@
+package wbs
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// buildWorkbook returns an xlsx archive holding the given parts
+func buildWorkbook(t *testing.T, parts map[string]string) *bytes.Reader {
+	buf := bytes.NewBufferString("")
+	archive := zip.NewWriter(buf)
+	for name, content := range parts {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func Test_ReadXLSX(t *testing.T) {
+	parts := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Notes" sheetId="1" r:id="rId1"/><sheet name="Tasks" sheetId="2" r:id="rId2"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships>
+<Relationship Id="rId1" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Target="/xl/worksheets/sheet2.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst><si><t>Task</t></si><si><t>Title</t></si><si><t>Parents</t></si><si><t>Duration</t></si>
+<si><t>1.10</t></si><si><r><t>Build </t></r><r><t>it</t></r></si><si><t>1.1</t></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>Not tasks</t></is></c></row></sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<worksheet><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c><c r="D1" t="s"><v>3</v></c></row>
+<row r="2"><c r="A2" t="s"><v>6</v></c><c r="B2" t="inlineStr"><is><t>Plan</t></is></c><c r="D2"><v>2</v></c></row>
+<row r="3"><c r="A3" t="s"><v>4</v></c><c r="B3" t="s"><v>5</v></c><c r="C3" t="s"><v>6</v></c><c r="D3"><v>1.5</v></c></row>
+<row r="4"></row>
+</sheetData></worksheet>`,
+	}
+
+	in := buildWorkbook(t, parts)
+	got, err := ReadXLSX(in, in.Size(), "Tasks")
+	if err != nil {
+		t.Fatalf("ReadXLSX() error = %v", err)
+	}
+	want := []Sheet{
+		{WBS: "1.1", Title: "Plan", Duration: 2},
+		{WBS: "1.10", Title: "Build it", Parents: "1.1", Duration: 1.5},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadXLSX() = %+v, want %+v", got, want)
+	}
+
+	if _, err := ReadXLSX(in, in.Size(), "Missing"); err == nil {
+		t.Errorf("ReadXLSX() of a missing sheet should fail")
+	}
+}
+
+func Test_ReadXLSX_dates(t *testing.T) {
+	parts := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Tasks" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+		"xl/styles.xml": `<styleSheet><numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy\-mm\-dd"/></numFmts>
+<cellXfs count="4"><xf numFmtId="0"/><xf numFmtId="164"/><xf numFmtId="14"/><xf numFmtId="2"/></cellXfs></styleSheet>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>
+<row r="1"><c r="A1" t="inlineStr"><is><t>Task</t></is></c><c r="B1" t="inlineStr"><is><t>Type</t></is></c><c r="C1" t="inlineStr"><is><t>Target</t></is></c><c r="D1" t="inlineStr"><is><t>Duration</t></is></c></row>
+<row r="2"><c r="A2" t="inlineStr"><is><t>1</t></is></c><c r="B2" t="inlineStr"><is><t>milestone</t></is></c><c r="C2" s="1"><v>46032</v></c></row>
+<row r="3"><c r="A3" t="inlineStr"><is><t>2</t></is></c><c r="B3" t="inlineStr"><is><t>milestone</t></is></c><c r="C3" s="2"><v>45351.5</v></c></row>
+<row r="4"><c r="A4" t="inlineStr"><is><t>3</t></is></c><c r="D4" s="3"><v>2</v></c></row>
+</sheetData></worksheet>`,
+	}
+
+	in := buildWorkbook(t, parts)
+	got, err := ReadXLSX(in, in.Size(), "")
+	if err != nil {
+		t.Fatalf("ReadXLSX() error = %v", err)
+	}
+	want := []Sheet{
+		{WBS: "1", Type: "milestone", Target: "2026-01-10"},
+		{WBS: "2", Type: "milestone", Target: "2024-02-29"},
+		{WBS: "3", Duration: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadXLSX() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_isDateFormat(t *testing.T) {
+	tests := map[string]bool{
+		"yyyy-mm-dd":      true,
+		"d-mmm-yy":        true,
+		"[$-409]mmmm d":   true,
+		"0.00":            false,
+		"h:mm:ss":         false,
+		`0 "days"`:        false,
+		"[Red]0.0;[Blue]": false,
+	}
+	for code, want := range tests {
+		if got := isDateFormat(code); got != want {
+			t.Errorf("isDateFormat(%q) = %v, want %v", code, got, want)
+		}
+	}
+}
+
+func Test_columnIndex(t *testing.T) {
+	tests := map[string]int{"A1": 0, "D12": 3, "Z3": 25, "AA1": 26, "": -1}
+	for ref, want := range tests {
+		if got := columnIndex(ref); got != want {
+			t.Errorf("columnIndex(%q) = %d, want %d", ref, got, want)
+		}
+	}
+}
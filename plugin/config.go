@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"wbspert/pkg/render"
+	"wbspert/pkg/wbs"
+)
+
+// project is the configuration for one GitHub project.  The yaml
+// tags are the keys accepted in the config file; the descriptions
+// are used for the JSON Schema.
+type project struct {
+	Name   string `yaml:"name" description:"GitHub project name"`
+	Output string `yaml:"output" description:"Markdown file the outputs are embedded in"`
+	// Options is no longer used; the outputs are selected by the
+	// fields below.  It is still accepted so existing configs load.
+	Options     string `yaml:"options,omitempty" description:"Deprecated: ignored" deprecated:"true"`
+	Level       int    `yaml:"level,omitempty" description:"The WBS level to use for PERT charts (default 3)"`
+	Kanban      bool   `yaml:"kanban,omitempty" description:"Build a kanban table"`
+	WBS         bool   `yaml:"wbs,omitempty" description:"Generate the WBS"`
+	WBSTable    bool   `yaml:"wbstable,omitempty" description:"Generate Markdown Table"`
+	PERT        bool   `yaml:"pert,omitempty" description:"Generate the PERT"`
+	Column      string `yaml:"column,omitempty" description:"Column field for Kanban table (default Status)"`
+	ActiveOnly  bool   `yaml:"activeonly,omitempty" description:"Only show incomplete tasks"`
+	BugList     bool   `yaml:"buglist,omitempty" description:"Generate a buglist"`
+	EpicList    bool   `yaml:"epiclist,omitempty" description:"Generate a checklist of epics"`
+	EpicDir     string `yaml:"epicdir,omitempty" description:"The location to write epic stories"`
+	EpicStories bool   `yaml:"epicstories,omitempty" description:"Write epic stories"`
+	Filter      string `yaml:"filter,omitempty" description:"Filter WBS Table and Kanban by a label value"`
+	KeepOrder   bool   `yaml:"keeporder,omitempty" description:"Keep the input order instead of sorting the tasks by WBS code"`
+}
+
+type cfg struct {
+	Projects []project         `yaml:"projects" description:"The projects to generate"`
+	Statuses []wbs.StatusStyle `yaml:"statuses,omitempty" description:"Status categories, colors and legend labels used by every project"`
+	// lines holds the line each project starts on in the config file
+	lines []int
+}
+
+// loadConfig reads the config file, rejecting keys that don't match
+// a config field.  Decoding errors include the line of the problem.
+func loadConfig(path string) (*cfg, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseConfig(data)
+}
+
+// parseConfig strictly decodes a config document
+func parseConfig(data []byte) (*cfg, error) {
+	config := &cfg{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	config.lines = projectLines(&doc)
+	return config, nil
+}
+
+// projectLines returns the line each item of the projects list
+// starts on
+func projectLines(doc *yaml.Node) []int {
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "projects" {
+			continue
+		}
+		var lines []int
+		for _, item := range root.Content[i+1].Content {
+			lines = append(lines, item.Line)
+		}
+		return lines
+	}
+	return nil
+}
+
+// validate returns the problems found in the config.  Nothing is
+// fetched from GitHub.
+func (c *cfg) validate() []string {
+	var problems []string
+	if len(c.Projects) == 0 {
+		problems = append(problems, "no projects configured")
+	}
+	for i, style := range c.Statuses {
+		if len(style.Statuses) == 0 {
+			problems = append(problems, fmt.Sprintf("status category %d (%s) has no statuses", i+1, style.Category))
+		}
+	}
+	for i, p := range c.Projects {
+		where := fmt.Sprintf("project %d", i+1)
+		if i < len(c.lines) {
+			where = fmt.Sprintf("line %d", c.lines[i])
+		}
+		for _, problem := range p.validate() {
+			problems = append(problems, fmt.Sprintf("%s: %s", where, problem))
+		}
+	}
+	return problems
+}
+
+// deprecations returns a warning for each deprecated setting used
+func (c *cfg) deprecations() []string {
+	var warnings []string
+	for _, p := range c.Projects {
+		if p.Options != "" {
+			warnings = append(warnings, fmt.Sprintf("%s: options is deprecated and ignored; use the output fields instead", p.Name))
+		}
+	}
+	return warnings
+}
+
+// validate returns the problems found in a project's config
+func (p *project) validate() []string {
+	var problems []string
+	if p.Name == "" {
+		problems = append(problems, "name is required")
+	}
+	if p.Output == "" {
+		problems = append(problems, "output is required")
+	}
+	if p.Level < 0 {
+		problems = append(problems, fmt.Sprintf("level %d is negative", p.Level))
+	}
+	if p.EpicStories {
+		if p.EpicDir == "" {
+			problems = append(problems, "epicstories needs an epicdir")
+		} else if info, err := os.Stat(p.EpicDir); err != nil {
+			problems = append(problems, fmt.Sprintf("epicdir %s does not exist", p.EpicDir))
+		} else if !info.IsDir() {
+			problems = append(problems, fmt.Sprintf("epicdir %s is not a directory", p.EpicDir))
+		}
+	}
+	if p.charts() == (render.Charts{}) {
+		problems = append(problems, "no outputs are enabled")
+	}
+	return problems
+}
+
+// configSchema returns the JSON Schema of the config file
+func configSchema() ([]byte, error) {
+	schema := objectSchema(reflect.TypeOf(cfg{}))
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	schema["title"] = "wbspert plugin config"
+	schema["required"] = []string{"projects"}
+	items := schema["properties"].(map[string]interface{})["projects"].(map[string]interface{})["items"].(map[string]interface{})
+	items["required"] = []string{"name", "output"}
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// objectSchema builds the schema of a struct from its yaml and
+// description tags.  Unexported fields are skipped.
+func objectSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		property := typeSchema(field.Type)
+		if desc := field.Tag.Get("description"); desc != "" {
+			property["description"] = desc
+		}
+		if field.Tag.Get("deprecated") == "true" {
+			property["deprecated"] = true
+		}
+		properties[name] = property
+	}
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+// typeSchema returns the schema for a config field type
+func typeSchema(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Struct:
+		return objectSchema(t)
+	default:
+		return map[string]interface{}{"type": "string"}
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func Test_parseConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			doc:  "projects:\n  - name: Roadmap\n    output: README.md\n    wbstable: true\n",
+		},
+		{
+			name:    "unknown key",
+			doc:     "projects:\n  - name: Roadmap\n    output: README.md\n    actveOnly: true\n",
+			wantErr: "line 4: field actveOnly not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := parseConfig([]byte(tt.doc))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("parseConfig() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseConfig() error = %v", err)
+			}
+			if !config.Projects[0].WBSTable || !reflect.DeepEqual(config.lines, []int{2}) {
+				t.Errorf("parseConfig() = %+v", config)
+			}
+		})
+	}
+}
+
+func Test_cfg_validate(t *testing.T) {
+	dir := t.TempDir()
+	doc := `projects:
+  - name: Roadmap
+    output: README.md
+    pert: true
+  - name: Epics
+    output: EPICS.md
+    epicstories: true
+    epicdir: ` + filepath.Join(dir, "missing") + `
+  - output: OTHER.md
+`
+	config, err := parseConfig([]byte(doc))
+	if err != nil {
+		t.Fatalf("parseConfig() error = %v", err)
+	}
+	want := []string{
+		"line 5: epicdir " + filepath.Join(dir, "missing") + " does not exist",
+		"line 9: name is required",
+		"line 9: no outputs are enabled",
+	}
+	if got := config.validate(); !reflect.DeepEqual(got, want) {
+		t.Errorf("validate() = %q, want %q", got, want)
+	}
+}
+
+func Test_configSchema(t *testing.T) {
+	got, err := configSchema()
+	if err != nil {
+		t.Fatalf("configSchema() error = %v", err)
+	}
+	got = append(got, '\n')
+	golden := "config.schema.json"
+	if *update {
+		if err := os.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("configSchema() doesn't match %s; run go test -update", golden)
+	}
+}
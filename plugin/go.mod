@@ -3,6 +3,13 @@ module wbsplugin
 go 1.16
 
 require (
+	ghprojects v0.0.0-00010101000000-000000000000
 	github.com/jessevdk/go-flags v1.5.0
 	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b
+	wbspert v0.0.0-00010101000000-000000000000
+)
+
+replace (
+	ghprojects => ../../ghprojects
+	wbspert => ../
 )
@@ -1,47 +1,222 @@
 package main
 
 import (
-	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"log"
 	"os"
-	"os/exec"
-	"strconv"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	"ghprojects/projects"
 
 	"github.com/jessevdk/go-flags"
-	"gopkg.in/yaml.v3"
-)
 
-type cfg struct {
-	Projects []struct {
-		Name        string
-		Output      string
-		Options     string
-		Level       int
-		Kanban      bool
-		WBS         bool
-		WBSTable    bool
-		PERT        bool
-		Column      string
-		ActiveOnly  bool
-		BugList     bool
-		EpicList    bool
-		EpicDir     string
-		EpicStories bool
-		Filter      string
-	}
-}
+	"wbspert/pkg/render"
+	"wbspert/pkg/wbs"
+)
 
 type pluginOpts struct {
 	ConfigFile string `short:"f" env:"PLUGIN_CONFIG_FILE"`
 	Token      string `short:"t" long:"token" env:"PLUGIN_GITHUB_TOKEN"`
 	Org        string `short:"o" long:"org" env:"PLUGIN_ORG" default:"ringsq"`
+	// Concurrency is the number of projects generated at once
+	Concurrency int `short:"n" long:"concurrency" env:"PLUGIN_CONCURRENCY" default:"4"`
+	// ContinueOnError keeps starting projects after one has failed
+	ContinueOnError bool `long:"continue-on-error" env:"PLUGIN_CONTINUE_ON_ERROR"`
+}
+
+// validateCommand checks the config file without calling GitHub
+type validateCommand struct {
+	opts *pluginOpts
+}
+
+// Execute reports the problems found in the config file
+func (c *validateCommand) Execute(args []string) error {
+	config, err := loadConfig(c.opts.ConfigFile)
+	if err != nil {
+		return err
+	}
+	for _, warning := range config.deprecations() {
+		fmt.Printf("warning: %s\n", warning)
+	}
+	problems := config.validate()
+	if len(problems) == 0 {
+		fmt.Printf("%s: %d project(s), no problems found\n", c.opts.ConfigFile, len(config.Projects))
+		return nil
+	}
+	fmt.Printf("%s: found %d problem(s):\n", c.opts.ConfigFile, len(problems))
+	for _, problem := range problems {
+		fmt.Printf("  %s\n", problem)
+	}
+	return fmt.Errorf("invalid config")
+}
+
+// schemaCommand prints the JSON Schema of the config file
+type schemaCommand struct{}
+
+// Execute writes the schema to stdout
+func (c *schemaCommand) Execute(args []string) error {
+	schema, err := configSchema()
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(schema))
+	return nil
+}
+
+// result is the outcome of generating the outputs for one project
+type result struct {
+	Name     string
+	Output   string
+	Duration time.Duration
+	Err      error
+	// Skipped is set when the project wasn't started because an
+	// earlier project failed
+	Skipped bool
+}
+
+// options returns the render options for the project, using the
+// same defaults as the wbspert command and the shared status
+// vocabulary
+func (p *project) options(statuses []wbs.StatusStyle) *render.Options {
+	opts := &render.Options{
+		Statuses:   statuses,
+		Level:      3,
+		ActiveOnly: p.ActiveOnly,
+		Filter:     p.Filter,
+		Column:     "Status",
+		Format:     render.PlantUMLFormat,
+		EpicDir:    p.EpicDir,
+		KeepOrder:  p.KeepOrder,
+	}
+	if p.Level > 0 {
+		opts.Level = p.Level
+	}
+	if p.Column != "" {
+		opts.Column = p.Column
+	}
+	return opts
+}
+
+// charts returns the outputs enabled for the project
+func (p *project) charts() render.Charts {
+	return render.Charts{
+		PERT:        p.PERT,
+		WBS:         p.WBS,
+		Table:       p.WBSTable,
+		Kanban:      p.Kanban,
+		BugList:     p.BugList,
+		EpicList:    p.EpicList,
+		EpicStories: p.EpicStories,
+	}
+}
+
+// generate loads the project from GitHub and embeds its outputs in
+// the project's output file.  The lock is held while the output file
+// is written so projects sharing a file don't overwrite each other.
+func generate(client wbs.ProjectClient, org string, p *project, statuses []wbs.StatusStyle, lock sync.Locker) error {
+	board, sheets, err := wbs.LoadProject(client, org, p.Name, false)
+	if err != nil {
+		return err
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	dst := render.Output{Path: p.Output, Embed: true}
+	return render.Generate(dst, nil, p.charts(), sheets, board, p.options(statuses))
+}
+
+// generateAll generates the projects using up to concurrency workers
+// and returns a result for each project in config order.  Every
+// project uses the given status vocabulary.  Unless continueOnError
+// is set no new projects are started once one fails.
+func generateAll(client wbs.ProjectClient, org string, configs []project, statuses []wbs.StatusStyle, concurrency int, continueOnError bool) []result {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	locks := make(map[string]*sync.Mutex)
+	for _, p := range configs {
+		path := filepath.Clean(p.Output)
+		if locks[path] == nil {
+			locks[path] = &sync.Mutex{}
+		}
+	}
+
+	results := make([]result, len(configs))
+	var failures int32
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				p := &configs[i]
+				log.Printf("Generating %s into %s", p.Name, p.Output)
+				started := time.Now()
+				err := generate(client, org, p, statuses, locks[filepath.Clean(p.Output)])
+				if err != nil {
+					log.Printf("Error generating %s: %s", p.Name, err)
+					atomic.AddInt32(&failures, 1)
+				}
+				results[i] = result{Name: p.Name, Output: p.Output, Duration: time.Since(started), Err: err}
+			}
+		}()
+	}
+	for i := range configs {
+		if !continueOnError && atomic.LoadInt32(&failures) > 0 {
+			results[i] = result{Name: configs[i].Name, Output: configs[i].Output, Skipped: true}
+			continue
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// summarize writes a line per project and returns the number of
+// projects that failed
+func summarize(w io.Writer, results []result) int {
+	failed := 0
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROJECT\tOUTPUT\tDURATION\tRESULT")
+	for _, r := range results {
+		status := "ok"
+		duration := r.Duration.Round(time.Millisecond).String()
+		switch {
+		case r.Skipped:
+			status = "skipped"
+			duration = "-"
+			failed++
+		case r.Err != nil:
+			status = fmt.Sprintf("failed: %s", r.Err)
+			failed++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Name, r.Output, duration, status)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "%d of %d projects succeeded\n", len(results)-failed, len(results))
+	return failed
 }
 
 func main() {
 	opts := &pluginOpts{}
 	parser := flags.NewParser(opts, flags.Default)
+	parser.SubcommandsOptional = true
+	parser.AddCommand("validate", "Check the config file", "Check every project in the config file without calling GitHub", &validateCommand{opts: opts})
+	parser.AddCommand("schema", "Print the config JSON Schema", "Print the JSON Schema of the config file for editor completion", &schemaCommand{})
 	_, err := parser.Parse()
+	if parser.Active != nil {
+		if err != nil {
+			os.Exit(1)
+		}
+		return
+	}
 	if err != nil {
 		if flgErr, ok := err.(*flags.Error); ok {
 			if flgErr.Type == flags.ErrHelp {
@@ -51,69 +226,22 @@ func main() {
 		fmt.Println(err.Error())
 		os.Exit(2)
 	}
-	yamlBytes, err := os.ReadFile(opts.ConfigFile)
+	config, err := loadConfig(opts.ConfigFile)
 	if err != nil {
 		log.Fatal(err)
 	}
+	for _, warning := range config.deprecations() {
+		log.Printf("warning: %s", warning)
+	}
 	if len(opts.Token) > 0 {
 		log.Printf("GITHUB_TOKEN has been set")
 	} else {
 		log.Fatal("GITHUB_TOKEN NOT set")
 	}
-	config := &cfg{}
-	err = yaml.Unmarshal(yamlBytes, config)
-	if err != nil {
-		log.Fatal(err)
-	}
-	for _, project := range config.Projects {
-		var args []string
-		args = append(args, "--github-token", opts.Token, "--org", opts.Org, "-e", "-i", "gh", "-j", project.Name, "-o", project.Output)
-		// fmt.Printf("wbsperf -i gh --github-token %s -e -j %s %s -o %s\n", opts.Token, project.Name, project.Options, project.Output)
-		if project.Column != "" {
-			args = append(args, "-c", project.Column)
-		}
-		if project.Filter != "" {
-			args = append(args, "-f", project.Filter)
-		}
-		if project.ActiveOnly {
-			args = append(args, "-a")
-		}
-		if project.Kanban {
-			args = append(args, "-k")
-		}
-		if project.WBS {
-			args = append(args, "-w")
-		}
-		if project.WBSTable {
-			args = append(args, "-t")
-		}
-		if project.PERT {
-			args = append(args, "-p")
-		}
-		if project.BugList {
-			args = append(args, "-b")
-		}
-		if project.EpicList {
-			args = append(args, "-E")
-		}
-		if project.EpicStories {
-			args = append(args, "-s")
-		}
-		if project.Level > 0 {
-			args = append(args, "-l", strconv.Itoa(project.Level))
-		}
-		if len(project.EpicDir) > 0 {
-			args = append(args, "-d", project.EpicDir)
-		}
-		cmd := exec.Command("wbspert", args...)
-		fmt.Println(cmd)
-		buf := bytes.NewBufferString("")
-		cmd.Stderr = buf
-		cmd.Stdout = buf
-		cmd.Env = append(cmd.Env, fmt.Sprintf("GITHUB_TOKEN=%s", opts.Token))
-		if err := cmd.Run(); err != nil {
-			log.Println("Error runing wbspert ", err)
-			log.Fatal(buf.String())
-		}
+
+	client := projects.NewClient(context.Background(), opts.Token)
+	results := generateAll(client, opts.Org, config.Projects, config.Statuses, opts.Concurrency, opts.ContinueOnError)
+	if summarize(os.Stdout, results) > 0 {
+		os.Exit(1)
 	}
 }
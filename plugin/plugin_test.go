@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"ghprojects/projects"
+)
+
+// failingClient fails every project and records the names requested
+type failingClient struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func (c *failingClient) GetProject(org, name string) (*projects.Board, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.names = append(c.names, name)
+	return nil, errors.New("not found")
+}
+
+func Test_generateAll(t *testing.T) {
+	configs := []project{
+		{Name: "one", Output: "a.md"},
+		{Name: "two", Output: "a.md"},
+		{Name: "three", Output: "b.md"},
+	}
+	t.Run("continue on error", func(t *testing.T) {
+		client := &failingClient{}
+		results := generateAll(client, "org", configs, nil, 2, true)
+		if len(client.names) != 3 {
+			t.Errorf("generateAll() requested %v, want all projects", client.names)
+		}
+		for i, r := range results {
+			if r.Name != configs[i].Name || r.Err == nil || r.Skipped {
+				t.Errorf("generateAll() result %d = %+v, want failure for %s", i, r, configs[i].Name)
+			}
+		}
+	})
+	t.Run("stop on error", func(t *testing.T) {
+		client := &failingClient{}
+		results := generateAll(client, "org", configs, nil, 1, false)
+		if results[0].Err == nil {
+			t.Errorf("generateAll() first result = %+v, want failure", results[0])
+		}
+		if !results[2].Skipped {
+			t.Errorf("generateAll() last result = %+v, want skipped", results[2])
+		}
+	})
+}
+
+// boardClient returns a board for every project except "missing"
+type boardClient struct{}
+
+func (c boardClient) GetProject(org, name string) (*projects.Board, error) {
+	if name == "missing" {
+		return nil, errors.New("not found")
+	}
+	return &projects.Board{Columns: []*projects.BoardColumn{
+		{Name: "Todo", Cards: []*projects.Card{{Title: name, Status: "Todo"}}},
+	}}, nil
+}
+
+func Test_generateAll_sharedOutput(t *testing.T) {
+	dir := t.TempDir()
+	shared := filepath.Join(dir, "README.md")
+	configs := []project{
+		{Name: "kanban", Output: shared, Kanban: true},
+		{Name: "table", Output: shared, WBSTable: true},
+		{Name: "pert", Output: shared, PERT: true},
+		{Name: "missing", Output: filepath.Join(dir, "other.md"), Kanban: true},
+		{Name: "bugs", Output: shared, BugList: true},
+		{Name: "epics", Output: shared, EpicList: true},
+	}
+	results := generateAll(boardClient{}, "org", configs, nil, len(configs), true)
+
+	for i, r := range results {
+		if r.Skipped || (r.Err != nil) != (configs[i].Name == "missing") {
+			t.Errorf("generateAll() result %d = %+v", i, r)
+		}
+	}
+	data, err := os.ReadFile(shared)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tag := range []string{"kanban", "wbsTable", "pert", "bug", "epic"} {
+		if !strings.Contains(string(data), "<!-- "+tag+":embed:start -->") {
+			t.Errorf("%s is missing the %s output:\n%s", shared, tag, data)
+		}
+	}
+
+	out := bytes.NewBufferString("")
+	if failed := summarize(out, results); failed != 1 {
+		t.Errorf("summarize() = %d, want 1", failed)
+	}
+	for _, want := range []string{"missing", "failed: not found", "5 of 6 projects succeeded"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("summarize() output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func Test_summarize(t *testing.T) {
+	out := bytes.NewBufferString("")
+	failed := summarize(out, []result{
+		{Name: "one", Output: "a.md"},
+		{Name: "two", Output: "a.md", Err: errors.New("not found")},
+		{Name: "three", Output: "b.md", Skipped: true},
+	})
+	if failed != 2 {
+		t.Errorf("summarize() = %d, want 2", failed)
+	}
+	for _, want := range []string{"failed: not found", "skipped", "1 of 3 projects succeeded"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("summarize() output missing %q:\n%s", want, out.String())
+		}
+	}
+}